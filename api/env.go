@@ -3,6 +3,8 @@ package api
 import (
 	"log"
 	"oauth2-console-go/driver"
+	"oauth2-console-go/pkg/mailer"
+	"oauth2-console-go/pkg/storage"
 
 	"github.com/go-redis/redis/v7"
 	"xorm.io/xorm"
@@ -12,6 +14,8 @@ type Env struct {
 	Orm          *xorm.EngineGroup
 	Redis        *redis.Client
 	RedisCluster *redis.ClusterClient
+	Mailer       mailer.Mailer
+	Storage      storage.Storage
 }
 
 var env = &Env{}
@@ -51,3 +55,17 @@ func InitRedisCluster() *redis.ClusterClient {
 
 	return env.RedisCluster
 }
+
+func InitMailer() (mailer.Mailer, error) {
+	var err error
+	env.Mailer, err = driver.NewMailer()
+
+	return env.Mailer, err
+}
+
+func InitStorage() (storage.Storage, error) {
+	var err error
+	env.Storage, err = driver.NewStorage()
+
+	return env.Storage, err
+}
@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"net/http"
+	"oauth2-console-go/api"
+	"oauth2-console-go/dto/apireq"
+	apiKeyRepo "oauth2-console-go/internal/system/api_key/repository"
+	apiKeySrv "oauth2-console-go/internal/system/api_key/service"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	tokenLibrary "oauth2-console-go/internal/token/library"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListApiKey
+// @Summary List Api Key - Api key 列表
+// @Produce json
+// @Accept json
+// @Tags ApiKey
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param account_id query int true "Account ID"
+// @Success 200 {object} apires.ListApiKey
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/api-keys [get]
+func ListApiKey(c *gin.Context) {
+	req := apireq.ListApiKey{}
+	err := c.Bind(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	akr := apiKeyRepo.NewRepository(env.Orm)
+	aks := apiKeySrv.NewService(sar, akr)
+
+	res, err := aks.ListApiKey(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// AddApiKey
+// @Summary Add Api Key - 新增 Api key，key 僅於建立時回傳一次
+// @Produce json
+// @Accept json
+// @Tags ApiKey
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param Body body apireq.AddApiKey true "Request Add Api Key"
+// @Success 200 {object} apires.AddApiKey
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500001","message":"Database insertion error"}"
+// @Router /v1/api-keys [post]
+func AddApiKey(c *gin.Context) {
+	req := apireq.AddApiKey{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	akr := apiKeyRepo.NewRepository(env.Orm)
+	aks := apiKeySrv.NewService(sar, akr)
+
+	res, err := aks.AddApiKey(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// DeleteApiKey
+// @Summary Delete Api Key - 撤銷 Api key
+// @Produce json
+// @Accept json
+// @Tags ApiKey
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param id path int true "Api Key ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500003","message":"Database delete error"}"
+// @Router /v1/api-keys/{id} [delete]
+func DeleteApiKey(c *gin.Context) {
+	apiKeyId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "api key id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	akr := apiKeyRepo.NewRepository(env.Orm)
+	aks := apiKeySrv.NewService(sar, akr)
+
+	err = aks.DeleteApiKey(accId, apiKeyId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
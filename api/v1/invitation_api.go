@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"net/http"
+	"oauth2-console-go/api"
+	"oauth2-console-go/dto/apireq"
+	invitationRepo "oauth2-console-go/internal/system/invitation/repository"
+	invitationSrv "oauth2-console-go/internal/system/invitation/service"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	tokenLibrary "oauth2-console-go/internal/token/library"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddInvitation
+// @Summary Add Invitation 邀請新帳號，寄送邀請信至受邀者的 email
+// @Produce json
+// @Accept json
+// @Tags Invitation
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param Body body apireq.AddInvitation true "Request Add Invitation"
+// @Success 200 {object} apires.Invitation
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/invitations [post]
+func AddInvitation(c *gin.Context) {
+	req := apireq.AddInvitation{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	ic := invitationRepo.NewRedis(env.RedisCluster)
+	is := invitationSrv.NewService(sar, ic, env.Mailer)
+
+	res, err := is.AddInvitation(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// AcceptInvitation
+// @Summary Accept Invitation 以邀請信中的 token 建立帳號，email 視為已驗證
+// @Produce json
+// @Accept json
+// @Tags Invitation
+// @Param Body body apireq.AcceptInvitation true "Request Accept Invitation"
+// @Success 200 {object} apires.SysAccount
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500001","message":"Database insertion error"}"
+// @Router /v1/invitations/accept [post]
+func AcceptInvitation(c *gin.Context) {
+	req := apireq.AcceptInvitation{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	ic := invitationRepo.NewRedis(env.RedisCluster)
+	is := invitationSrv.NewService(sar, ic, env.Mailer)
+
+	res, err := is.AcceptInvitation(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
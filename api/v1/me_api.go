@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"net/http"
+	"oauth2-console-go/api"
+	"oauth2-console-go/dto/apireq"
+	loginLogRepo "oauth2-console-go/internal/system/login_log/repository"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	sysAccSrv "oauth2-console-go/internal/system/sys_account/service"
+	verificationRepo "oauth2-console-go/internal/system/verification/repository"
+	verificationSrv "oauth2-console-go/internal/system/verification/service"
+	tokenRepo "oauth2-console-go/internal/token/repository"
+	tokenSrv "oauth2-console-go/internal/token/service"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMe
+// @Summary Get Me 取得目前登入帳號的個人資料
+// @Produce json
+// @Accept json
+// @Tags Me
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Success 200 {object} apires.SysAccount
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/me [get]
+func GetMe(c *gin.Context) {
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	sas := sysAccSrv.NewService(sar)
+
+	res, err := sas.GetProfile(c.GetInt("account_id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// EditMe
+// @Summary Edit Me 編輯目前登入帳號的個人資料，email 變更時寄送新的驗證信
+// @Produce json
+// @Accept json
+// @Tags Me
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param Body body apireq.EditProfile true "Request Edit Profile"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/me [put]
+func EditMe(c *gin.Context) {
+	req := apireq.EditProfile{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	sas := sysAccSrv.NewService(sar)
+
+	err = sas.EditProfile(c.GetInt("account_id"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// email 變更後寄送驗證信，已驗證的帳號不會重寄，失敗可透過重寄 API 再次寄送
+	vc := verificationRepo.NewRedis(env.RedisCluster)
+	vs := verificationSrv.NewService(sar, vc, env.Mailer)
+	_ = vs.SendVerification(c.GetInt("account_id"))
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// ChangeMyPassword
+// @Summary Change My Password 變更密碼，其他裝置將被登出並回傳新的 token
+// @Produce json
+// @Accept json
+// @Tags Me
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param Body body apireq.ChangePassword true "Request Change Password"
+// @Success 200 {object} apires.SysAccountToken
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/me/password [post]
+func ChangeMyPassword(c *gin.Context) {
+	req := apireq.ChangePassword{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	req.UserAgent = c.Request.UserAgent()
+	req.Ip = c.ClientIP()
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+
+	res, err := ts.ChangePassword(c.GetInt("account_id"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"net/http"
+	"oauth2-console-go/api"
+	"oauth2-console-go/dto/apireq"
+	mfaSrv "oauth2-console-go/internal/system/mfa/service"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	tokenLibrary "oauth2-console-go/internal/token/library"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnrollMfaTotp
+// @Summary Enroll Mfa Totp 設定兩步驟驗證，取得 TOTP secret 及 recovery codes
+// @Produce json
+// @Accept json
+// @Tags Mfa
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param Body body apireq.EnrollMfaTotp true "Request Enroll Mfa Totp"
+// @Success 200 {object} apires.MfaTotpEnroll
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/mfa/totp [post]
+func EnrollMfaTotp(c *gin.Context) {
+	req := apireq.EnrollMfaTotp{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	ms := mfaSrv.NewService(sar)
+	res, err := ms.EnrollTotp(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ActivateMfaTotp
+// @Summary Activate Mfa Totp 以 authenticator app 產生的 code 啟用兩步驟驗證
+// @Produce json
+// @Accept json
+// @Tags Mfa
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param Body body apireq.ActivateMfaTotp true "Request Activate Mfa Totp"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/mfa/totp/activate [post]
+func ActivateMfaTotp(c *gin.Context) {
+	req := apireq.ActivateMfaTotp{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	ms := mfaSrv.NewService(sar)
+	err = ms.ActivateTotp(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// DisableMfaTotp
+// @Summary Disable Mfa Totp 停用兩步驟驗證
+// @Produce json
+// @Accept json
+// @Tags Mfa
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param Body body apireq.DisableMfaTotp true "Request Disable Mfa Totp"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Forbidden error"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/mfa/totp [delete]
+func DisableMfaTotp(c *gin.Context) {
+	req := apireq.DisableMfaTotp{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	ms := mfaSrv.NewService(sar)
+	err = ms.DisableTotp(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
@@ -5,6 +5,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"oauth2-console-go/api"
+	"oauth2-console-go/config"
 	"oauth2-console-go/dto/apireq"
 	"oauth2-console-go/dto/model"
 	clientRepo "oauth2-console-go/internal/oauth/client/repository"
@@ -21,15 +22,24 @@ import (
 )
 
 // ListOauthClient
-// @Summary List Oauth Client - Oauth Client App 列表
+// @Summary List Oauth Client - Oauth Client App 列表，admin 以外只列出自己的 client
 // @Produce json
 // @Accept json
 // @Tags Oauth Client
 // @Security Bearer
-// @Param Bearer header string true "JWT Token"
+// @Param Authorization header string true "Bearer {JWT Token}"
 // @Param account_id query int true "Account ID"
 // @Param page query int true "Page"
 // @Param per_page query int true "PerPage"
+// @Param keyword query string false "Client ID 或名稱包含"
+// @Param domain query string false "Domain 包含"
+// @Param owner_id query int false "擁有者 Account ID(admin 限定)"
+// @Param scope query string false "具有此授權"
+// @Param created_from query string false "建立時間起(RFC3339)"
+// @Param created_to query string false "建立時間迄(RFC3339)"
+// @Param updated_from query string false "更新時間起(RFC3339)"
+// @Param updated_to query string false "更新時間迄(RFC3339)"
+// @Param sort query string false "排序 e.g. name,-created_at (id, name, domain, sys_account_id, created_at, updated_at)"
 // @Success 200 {object} apires.ListOauthClient
 // @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
 // @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
@@ -65,9 +75,9 @@ func ListOauthClient(c *gin.Context) {
 	sar := sysAccRepo.NewRepository(env.Orm)
 	occ := clientRepo.NewCache(env.RedisCluster)
 	ocr := clientRepo.NewRepository(env.Orm)
-	ocs := clientSrv.NewService(sar, ocr, occ)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
 
-	res, err := ocs.ListClient(req.AccountId, req.Page, req.PerPage)
+	res, err := ocs.ListClient(&req)
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -82,7 +92,7 @@ func ListOauthClient(c *gin.Context) {
 // @Accept json
 // @Tags Oauth Client
 // @Security Bearer
-// @Param Bearer header string true "JWT Token"
+// @Param Authorization header string true "Bearer {JWT Token}"
 // @Param client_id path string true "Oauth Client ID"
 // @Param account_id query int true "Account ID"
 // @Success 200 {object} apires.OauthClient
@@ -115,7 +125,7 @@ func GetOauthClient(c *gin.Context) {
 	osr := scopeRepo.NewRepository(env.Orm)
 	occ := clientRepo.NewCache(env.RedisCluster)
 	ocr := clientRepo.NewRepository(env.Orm)
-	ocs := clientSrv.NewService(sar, ocr, occ)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
 
 	res, err := ocs.GetClient(accId, clientId, osr)
 	if err != nil {
@@ -127,19 +137,18 @@ func GetOauthClient(c *gin.Context) {
 }
 
 // AddOauthClient
-// @Summary Add Oauth Client - 新增 Client APP
+// @Summary Add Oauth Client - 新增 Client APP，secret 由伺服器產生且只在此時回傳
 // @Produce json
 // @Accept json
 // @Tags Oauth Client
 // @Security Bearer
-// @Param Bearer header string true "JWT Token"
+// @Param Authorization header string true "Bearer {JWT Token}"
 // @Param account_id formData int true "Account id"
 // @Param id formData string true "Client Id"
-// @Param secret formData string true "Client Secret"
 // @Param domain formData string true "Client Domain"
 // @Param name formData string true "Client Name"
 // @Param file formData file true "Client Icon Image"
-// @Success 200 {string} string "{}"
+// @Success 200 {object} apires.OauthClientSecret
 // @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
 // @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
 // @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
@@ -163,11 +172,12 @@ func AddOauthClient(c *gin.Context) {
 	}
 
 	// validate upload image
-	file, fileName, fileExtension, err := helper.CheckFormUploadImage(c, "file", 2) // 2MB
+	file, fileName, fileExtension, err := helper.CheckFormUploadImage(c, "file", config.ClientIconSizeLimit)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
+	defer file.Close()
 
 	// 驗證 jwt user == user_id
 	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
@@ -180,7 +190,7 @@ func AddOauthClient(c *gin.Context) {
 	sar := sysAccRepo.NewRepository(env.Orm)
 	occ := clientRepo.NewCache(env.RedisCluster)
 	ocr := clientRepo.NewRepository(env.Orm)
-	ocs := clientSrv.NewService(sar, ocr, occ)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
 
 	request := apireq.AddOauthClientWithFile{
 		AddOauthClient: &req,
@@ -189,30 +199,33 @@ func AddOauthClient(c *gin.Context) {
 		FileExtension:  fileExtension,
 	}
 
-	err = ocs.AddClient(&request)
+	res, err := ocs.AddClient(&request)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, map[string]interface{}{})
+	c.JSON(http.StatusOK, res)
 }
 
 // EditOauthClient
-// @Summary Edit Oauth Client - 編輯 Client APP
+// @Summary Edit Oauth Client - 編輯 Client APP，授權流程組合不相容時回傳 400
 // @Produce json
 // @Accept json
 // @Tags Oauth Client
 // @Security Bearer
-// @Param Bearer header string true "JWT Token"
+// @Param Authorization header string true "Bearer {JWT Token}"
 // @Param client_id path string true "Oauth Client ID"
 // @Param account_id formData int true "Account id"
-// @Param secret formData string true "Client Secret"
 // @Param domain formData string true "Client Domain"
 // @Param name formData string true "Client Name"
 // @Param has_image formData bool true "Upload Image for Update"
 // @Param file formData file true "Client Icon Image"
 // @Param scope_list formData string true "Client Scope List(After json stringify)"
+// @Param grant_types formData []string false "Grant Types(authorization_code, client_credentials, refresh_token, device_code)" collectionFormat(multi)
+// @Param response_types formData []string false "Response Types(code, token)" collectionFormat(multi)
+// @Param pkce_required formData bool false "PKCE Required"
+// @Param pkce_s256_only formData bool false "PKCE S256 Only"
 // @Success 200 {string} string "{}"
 // @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
 // @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
@@ -255,11 +268,12 @@ func EditOauthClient(c *gin.Context) {
 
 	if *req.HasImage {
 		// validate upload image
-		file, fileName, fileExtension, err = helper.CheckFormUploadImage(c, "file", 2) // 2MB
+		file, fileName, fileExtension, err = helper.CheckFormUploadImage(c, "file", config.ClientIconSizeLimit)
 		if err != nil {
 			_ = c.Error(err)
 			return
 		}
+		defer file.Close()
 	}
 
 	// 驗證 jwt user == user_id
@@ -274,7 +288,7 @@ func EditOauthClient(c *gin.Context) {
 	osr := scopeRepo.NewRepository(env.Orm)
 	occ := clientRepo.NewCache(env.RedisCluster)
 	ocr := clientRepo.NewRepository(env.Orm)
-	ocs := clientSrv.NewService(sar, ocr, occ)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
 
 	request := apireq.EditOauthClientWithFile{
 		EditOauthClient: &req,
@@ -292,3 +306,424 @@ func EditOauthClient(c *gin.Context) {
 
 	c.JSON(http.StatusOK, map[string]interface{}{})
 }
+
+// DeleteOauthClient
+// @Summary Delete Oauth Client 刪除 Client APP，授權伺服器將立即停止接受此 client
+// @Produce json
+// @Accept json
+// @Tags Oauth Client
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param client_id path string true "Oauth Client ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500003","message":"Database delete error"}"
+// @Router /v1/oauth/clients/{client_id} [delete]
+func DeleteOauthClient(c *gin.Context) {
+	clientId := c.Param("id")
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	occ := clientRepo.NewCache(env.RedisCluster)
+	ocr := clientRepo.NewRepository(env.Orm)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
+
+	err = ocs.DeleteClient(accId, clientId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// TransferOauthClient
+// @Summary Transfer Oauth Client - 將 Client APP 轉移給其他帳號，新的擁有者須為 editor 以上
+// @Produce json
+// @Accept json
+// @Tags Oauth Client
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param client_id path string true "Oauth Client ID"
+// @Param Body body apireq.TransferOauthClient true "Request Transfer Oauth Client"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/oauth/clients/{client_id}/transfer [post]
+func TransferOauthClient(c *gin.Context) {
+	clientId := c.Param("id")
+
+	req := apireq.TransferOauthClient{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	occ := clientRepo.NewCache(env.RedisCluster)
+	ocr := clientRepo.NewRepository(env.Orm)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
+
+	err = ocs.TransferClient(clientId, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// RotateOauthClientSecret
+// @Summary Rotate Oauth Client Secret 重新產生 Client APP 的 secret，新的 secret 只在此時回傳，舊的 secret 於寬限期內仍可使用(預設 24 小時)
+// @Produce json
+// @Accept json
+// @Tags Oauth Client
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param client_id path string true "Oauth Client ID"
+// @Param Body body apireq.RotateOauthClientSecret true "Request Rotate Oauth Client Secret"
+// @Success 200 {object} apires.OauthClientSecret
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/oauth/clients/{client_id}/secrets [post]
+func RotateOauthClientSecret(c *gin.Context) {
+	clientId := c.Param("id")
+
+	req := apireq.RotateOauthClientSecret{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	occ := clientRepo.NewCache(env.RedisCluster)
+	ocr := clientRepo.NewRepository(env.Orm)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
+
+	res, err := ocs.RotateSecret(clientId, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ListOauthClientSecret
+// @Summary List Oauth Client Secret 列出 Client APP 目前及寬限期內的舊 secret 的建立及到期時間，不回傳 secret
+// @Produce json
+// @Accept json
+// @Tags Oauth Client
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param client_id path string true "Oauth Client ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {object} apires.ListOauthClientSecret
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/oauth/clients/{client_id}/secrets [get]
+func ListOauthClientSecret(c *gin.Context) {
+	clientId := c.Param("id")
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	occ := clientRepo.NewCache(env.RedisCluster)
+	ocr := clientRepo.NewRepository(env.Orm)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
+
+	res, err := ocs.ListSecret(accId, clientId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// RevokeOauthClientPreviousSecret
+// @Summary Revoke Oauth Client Previous Secret 提前撤銷輪替前的舊 secret
+// @Produce json
+// @Accept json
+// @Tags Oauth Client
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param client_id path string true "Oauth Client ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/oauth/clients/{client_id}/secrets/previous [delete]
+func RevokeOauthClientPreviousSecret(c *gin.Context) {
+	clientId := c.Param("id")
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	occ := clientRepo.NewCache(env.RedisCluster)
+	ocr := clientRepo.NewRepository(env.Orm)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
+
+	err = ocs.RevokePreviousSecret(accId, clientId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// ListOauthClientRedirectUri
+// @Summary List Oauth Client Redirect Uri - Client APP 登記的 redirect uri 列表
+// @Produce json
+// @Accept json
+// @Tags Oauth Client
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param client_id path string true "Oauth Client ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {object} apires.ListOauthClientRedirectUri
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/oauth/clients/{client_id}/redirect-uris [get]
+func ListOauthClientRedirectUri(c *gin.Context) {
+	clientId := c.Param("id")
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	occ := clientRepo.NewCache(env.RedisCluster)
+	ocr := clientRepo.NewRepository(env.Orm)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
+
+	res, err := ocs.ListRedirectUri(accId, clientId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// AddOauthClientRedirectUri
+// @Summary Add Oauth Client Redirect Uri - 登記 redirect uri，須為絕對路徑且不可帶 fragment，除 loopback 外須使用 https，原生 app 使用反向網域的自訂 scheme
+// @Produce json
+// @Accept json
+// @Tags Oauth Client
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param client_id path string true "Oauth Client ID"
+// @Param Body body apireq.AddOauthClientRedirectUri true "Request Add Oauth Client Redirect Uri"
+// @Success 200 {object} model.OauthClientRedirectUri
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500001","message":"Database insertion error"}"
+// @Router /v1/oauth/clients/{client_id}/redirect-uris [post]
+func AddOauthClientRedirectUri(c *gin.Context) {
+	clientId := c.Param("id")
+
+	req := apireq.AddOauthClientRedirectUri{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	occ := clientRepo.NewCache(env.RedisCluster)
+	ocr := clientRepo.NewRepository(env.Orm)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
+
+	res, err := ocs.AddRedirectUri(clientId, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// DeleteOauthClientRedirectUri
+// @Summary Delete Oauth Client Redirect Uri - 刪除 redirect uri
+// @Produce json
+// @Accept json
+// @Tags Oauth Client
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param client_id path string true "Oauth Client ID"
+// @Param redirect_uri_id path int true "Redirect Uri ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500003","message":"Database delete error"}"
+// @Router /v1/oauth/clients/{client_id}/redirect-uris/{redirect_uri_id} [delete]
+func DeleteOauthClientRedirectUri(c *gin.Context) {
+	clientId := c.Param("id")
+
+	redirectUriId, err := strconv.Atoi(c.Param("redirect_uri_id"))
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "redirect uri id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	occ := clientRepo.NewCache(env.RedisCluster)
+	ocr := clientRepo.NewRepository(env.Orm)
+	ocs := clientSrv.NewService(sar, ocr, occ, env.Storage)
+
+	err = ocs.DeleteRedirectUri(accId, clientId, redirectUriId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
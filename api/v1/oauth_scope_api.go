@@ -22,10 +22,19 @@ import (
 // @Accept json
 // @Tags Oauth Scope
 // @Security Bearer
-// @Param Bearer header string true "JWT Token"
+// @Param Authorization header string true "Bearer {JWT Token}"
 // @Param account_id query int true "Account ID"
 // @Param page query int true "Page"
 // @Param per_page query int true "PerPage"
+// @Param keyword query string false "Scope、名稱或 path 包含"
+// @Param method query string false "Method"
+// @Param is_disable query bool false "是否停用"
+// @Param scope query string false "類別或完整 scope"
+// @Param created_from query string false "建立時間起(RFC3339)"
+// @Param created_to query string false "建立時間迄(RFC3339)"
+// @Param updated_from query string false "更新時間起(RFC3339)"
+// @Param updated_to query string false "更新時間迄(RFC3339)"
+// @Param sort query string false "排序 e.g. scope,-created_at (id, scope, name, path, method, created_at, updated_at)"
 // @Success 200 {object} apires.ListOauthScope
 // @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
 // @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
@@ -64,7 +73,7 @@ func ListOauthScope(c *gin.Context) {
 	osr := scopeRepo.NewRepository(env.Orm)
 	oss := scopeSrv.NewService(sar, osr, osc, occ)
 
-	res, err := oss.ListScope(req.AccountId, req.Page, req.PerPage)
+	res, err := oss.ListScope(&req)
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -79,7 +88,7 @@ func ListOauthScope(c *gin.Context) {
 // @Accept json
 // @Tags Oauth Scope
 // @Security Bearer
-// @Param Bearer header string true "JWT Token"
+// @Param Authorization header string true "Bearer {JWT Token}"
 // @Param scope_id path int true "Oauth Scope ID"
 // @Param account_id query int true "Account ID"
 // @Success 200 {object} model.OauthScope
@@ -135,7 +144,7 @@ func GetOauthScope(c *gin.Context) {
 // @Accept json
 // @Tags Oauth Scope
 // @Security Bearer
-// @Param Bearer header string true "JWT Token"
+// @Param Authorization header string true "Bearer {JWT Token}"
 // @Param Body body apireq.AddOauthScope true "Request Add Oauth Scope"
 // @Success 200 {string} string "{}"
 // @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
@@ -190,7 +199,7 @@ func AddOauthScope(c *gin.Context) {
 // @Accept json
 // @Tags Oauth Scope
 // @Security Bearer
-// @Param Bearer header string true "JWT Token"
+// @Param Authorization header string true "Bearer {JWT Token}"
 // @Param scope_id path string true "Oauth Scope ID"
 // @Param Body body apireq.EditOauthScope true "Request Edit Oauth Scope"
 // @Success 200 {string} string "{}"
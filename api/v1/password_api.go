@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"net/http"
+	"oauth2-console-go/api"
+	"oauth2-console-go/dto/apireq"
+	loginLogRepo "oauth2-console-go/internal/system/login_log/repository"
+	passwordSrv "oauth2-console-go/internal/system/password/service"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	tokenRepo "oauth2-console-go/internal/token/repository"
+	tokenSrv "oauth2-console-go/internal/token/service"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForgotPassword
+// @Summary Forgot Password 寄送重設密碼信件，帳號不存在時同樣回傳成功
+// @Produce json
+// @Accept json
+// @Tags Password
+// @Param Body body apireq.ForgotPassword true "Request Forgot Password"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/password/forgot [post]
+func ForgotPassword(c *gin.Context) {
+	req := apireq.ForgotPassword{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+	ps := passwordSrv.NewService(sar, ts, env.Mailer)
+
+	err = ps.ForgotPassword(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// ResetPassword
+// @Summary Reset Password 以重設密碼信件中的 token 設定新密碼，並登出所有裝置
+// @Produce json
+// @Accept json
+// @Tags Password
+// @Param Body body apireq.ResetPassword true "Request Reset Password"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/password/reset [post]
+func ResetPassword(c *gin.Context) {
+	req := apireq.ResetPassword{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+	ps := passwordSrv.NewService(sar, ts, env.Mailer)
+
+	err = ps.ResetPassword(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
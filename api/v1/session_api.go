@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"net/http"
+	"oauth2-console-go/api"
+	"oauth2-console-go/dto/apireq"
+	loginLogRepo "oauth2-console-go/internal/system/login_log/repository"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	tokenLibrary "oauth2-console-go/internal/token/library"
+	tokenRepo "oauth2-console-go/internal/token/repository"
+	tokenSrv "oauth2-console-go/internal/token/service"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSession
+// @Summary List Session - 登入裝置列表
+// @Produce json
+// @Accept json
+// @Tags Session
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param account_id query int true "Account ID"
+// @Success 200 {object} apires.ListSysAccountSession
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500005","message":"Redis server error"}"
+// @Router /v1/sessions [get]
+func ListSession(c *gin.Context) {
+	req := apireq.ListSysAccountSession{}
+	err := c.Bind(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+
+	res, err := ts.ListSession(req.AccountId, c.GetString("session_id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// RevokeSession
+// @Summary Revoke Session - 登出指定裝置
+// @Produce json
+// @Accept json
+// @Tags Session
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param session_id path string true "Session ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500005","message":"Redis server error"}"
+// @Router /v1/sessions/{session_id} [delete]
+func RevokeSession(c *gin.Context) {
+	sessionId := c.Param("id")
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+
+	err = ts.RevokeSession(accId, sessionId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
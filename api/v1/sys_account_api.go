@@ -0,0 +1,510 @@
+package v1
+
+import (
+	"net/http"
+	"oauth2-console-go/api"
+	"oauth2-console-go/dto/apireq"
+	loginLogRepo "oauth2-console-go/internal/system/login_log/repository"
+	loginLogSrv "oauth2-console-go/internal/system/login_log/service"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	sysAccSrv "oauth2-console-go/internal/system/sys_account/service"
+	verificationRepo "oauth2-console-go/internal/system/verification/repository"
+	verificationSrv "oauth2-console-go/internal/system/verification/service"
+	tokenLibrary "oauth2-console-go/internal/token/library"
+	tokenRepo "oauth2-console-go/internal/token/repository"
+	tokenSrv "oauth2-console-go/internal/token/service"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSysAccount
+// @Summary List Sys Account 帳號列表
+// @Produce json
+// @Accept json
+// @Tags SysAccount
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param account_id query int true "Account ID"
+// @Param page query int true "Page"
+// @Param per_page query int true "PerPage"
+// @Success 200 {object} apires.ListSysAccount
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/accounts [get]
+func ListSysAccount(c *gin.Context) {
+	req := apireq.ListSysAccount{}
+	err := c.Bind(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	sas := sysAccSrv.NewService(sar)
+
+	res, err := sas.ListAccount(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// GetSysAccount
+// @Summary Get Sys Account 取得帳號
+// @Produce json
+// @Accept json
+// @Tags SysAccount
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param id path int true "Account ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {object} apires.SysAccount
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/accounts/{id} [get]
+func GetSysAccount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	sas := sysAccSrv.NewService(sar)
+
+	res, err := sas.GetAccount(accId, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// AddSysAccount
+// @Summary Add Sys Account 新增帳號，並寄送 email 驗證信
+// @Produce json
+// @Accept json
+// @Tags SysAccount
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param Body body apireq.AddSysAccount true "Request Add Sys Account"
+// @Success 200 {object} apires.SysAccount
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500001","message":"Database insertion error"}"
+// @Router /v1/accounts [post]
+func AddSysAccount(c *gin.Context) {
+	req := apireq.AddSysAccount{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	sas := sysAccSrv.NewService(sar)
+
+	res, err := sas.AddAccount(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// 寄送驗證信，失敗不影響建立帳號，可透過重寄 API 再次寄送
+	vc := verificationRepo.NewRedis(env.RedisCluster)
+	vs := verificationSrv.NewService(sar, vc, env.Mailer)
+	_ = vs.SendVerification(res.Id)
+
+	c.JSON(http.StatusOK, res)
+}
+
+// EditSysAccount
+// @Summary Edit Sys Account 編輯帳號，角色變更時撤銷帳號所有的 token，email 變更時寄送新的驗證信
+// @Produce json
+// @Accept json
+// @Tags SysAccount
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param id path int true "Account ID"
+// @Param Body body apireq.EditSysAccount true "Request Edit Sys Account"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/accounts/{id} [put]
+func EditSysAccount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	req := apireq.EditSysAccount{}
+	err = c.BindJSON(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	sas := sysAccSrv.NewService(sar)
+
+	revoke, err := sas.EditAccount(id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// 角色或兩步驟驗證要求變更，撤銷帳號所有的 token，重新登入後取得新的角色
+	if revoke {
+		llr := loginLogRepo.NewRepository(env.Orm)
+		tc := tokenRepo.NewRedis(env.RedisCluster)
+		ts := tokenSrv.NewService(sar, llr, tc)
+		err = ts.LogoutAll(id)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	// email 變更後寄送驗證信，已驗證的帳號不會重寄，失敗可透過重寄 API 再次寄送
+	vc := verificationRepo.NewRedis(env.RedisCluster)
+	vs := verificationSrv.NewService(sar, vc, env.Mailer)
+	_ = vs.SendVerification(id)
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// DisableSysAccount
+// @Summary Disable Sys Account 停用帳號，並撤銷帳號所有的 token
+// @Produce json
+// @Accept json
+// @Tags SysAccount
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param id path int true "Account ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/accounts/{id}/disable [post]
+func DisableSysAccount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	sas := sysAccSrv.NewService(sar)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+
+	// 先標記為停用，確保資料庫更新後 TokenAuth 一定會拒絕帳號的 token
+	err = ts.DisableAccount(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = sas.DisableAccount(accId, id)
+	if err != nil {
+		_ = ts.ResetAccountDisabled(id)
+		_ = c.Error(err)
+		return
+	}
+
+	// 撤銷帳號所有的 token
+	err = ts.LogoutAll(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// EnableSysAccount
+// @Summary Enable Sys Account 重新啟用帳號
+// @Produce json
+// @Accept json
+// @Tags SysAccount
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param id path int true "Account ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/accounts/{id}/enable [post]
+func EnableSysAccount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	sas := sysAccSrv.NewService(sar)
+
+	err = sas.EnableAccount(accId, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+	err = ts.EnableAccount(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// UnlockSysAccount
+// @Summary Unlock Sys Account 解除登入失敗造成的帳號鎖定
+// @Produce json
+// @Accept json
+// @Tags SysAccount
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param id path int true "Account ID"
+// @Param account_id query int true "Account ID"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500005","message":"Redis server error"}"
+// @Router /v1/accounts/{id}/unlock [post]
+func UnlockSysAccount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	accIdStr := c.Query("account_id")
+	accId, err := strconv.Atoi(accIdStr)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "account id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, accId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+
+	err = ts.UnlockAccount(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// ListSysAccountLoginLog
+// @Summary List Sys Account Login Log 登入紀錄列表
+// @Produce json
+// @Accept json
+// @Tags SysAccount
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param id path int true "Account ID"
+// @Param account_id query int true "Account ID"
+// @Param page query int true "Page"
+// @Param per_page query int true "PerPage"
+// @Success 200 {object} apires.ListLoginLog
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
+// @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/accounts/{id}/logins [get]
+func ListSysAccountLoginLog(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "id format error.", err)
+		_ = c.Error(err)
+		return
+	}
+
+	req := apireq.ListLoginLog{}
+	err = c.Bind(&req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+	req.SysAccountId = id
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		err = er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(err)
+		return
+	}
+
+	// 驗證 jwt user == user_id
+	err = tokenLibrary.CheckJWTAccountId(c, req.AccountId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	lls := loginLogSrv.NewService(sar, llr)
+
+	res, err := lls.ListLoginLog(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
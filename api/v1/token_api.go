@@ -1,14 +1,18 @@
 package v1
 
 import (
+	"io"
 	"net/http"
 	"oauth2-console-go/api"
 	"oauth2-console-go/dto/apireq"
+	loginLogRepo "oauth2-console-go/internal/system/login_log/repository"
 	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	tokenLibrary "oauth2-console-go/internal/token/library"
 	tokenRepo "oauth2-console-go/internal/token/repository"
 	tokenSrv "oauth2-console-go/internal/token/service"
 	"oauth2-console-go/pkg/er"
 	"oauth2-console-go/pkg/valider"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,6 +28,8 @@ import (
 // @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
 // @Failure 403 {object} er.AppErrorMsg "{"code":"400403","message":"Permission denied"}"
 // @Failure 404 {object} er.AppErrorMsg "{"code":"400404","message":"Resource not found"}"
+// @Failure 423 {object} er.AppErrorMsg "{"code":"400411","message":"Account locked error"}"
+// @Failure 429 {object} er.AppErrorMsg "{"code":"400001","message":"Limit exceeded error"}"
 // @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
 // @Router /v1/token [post]
 func GetToken(c *gin.Context) {
@@ -43,10 +49,14 @@ func GetToken(c *gin.Context) {
 		return
 	}
 
+	req.UserAgent = c.Request.UserAgent()
+	req.Ip = c.ClientIP()
+
 	env := api.GetEnv()
 	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
 	tc := tokenRepo.NewRedis(env.RedisCluster)
-	ts := tokenSrv.NewService(sar, tc)
+	ts := tokenSrv.NewService(sar, llr, tc)
 	res, err := ts.GenToken(&req)
 	if err != nil {
 		_ = c.Error(err)
@@ -55,3 +65,168 @@ func GetToken(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+// VerifyMfa
+// @Summary Verify Mfa 兩步驟驗證，以 mfa token 及 TOTP / recovery code 取得 token
+// @Produce json
+// @Accept json
+// @Tags Token
+// @Param Body body apireq.VerifySysAccountMfa true "Request Verify Sys Account Mfa"
+// @Success 200 {object} apires.SysAccountToken
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 429 {object} er.AppErrorMsg "{"code":"400001","message":"Limit exceeded error"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/token/mfa [post]
+func VerifyMfa(c *gin.Context) {
+	req := apireq.VerifySysAccountMfa{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	req.UserAgent = c.Request.UserAgent()
+	req.Ip = c.ClientIP()
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+	res, err := ts.VerifyMfa(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// RefreshToken
+// @Summary Refresh Token 更新 token
+// @Produce json
+// @Accept json
+// @Tags Token
+// @Param Body body apireq.RefreshSysAccountToken true "Request Refresh Sys Account Token"
+// @Success 200 {object} apires.SysAccountToken
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/token/refresh [post]
+func RefreshToken(c *gin.Context) {
+	req := apireq.RefreshSysAccountToken{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+	res, err := ts.RefreshToken(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Logout
+// @Summary Logout 登出，撤銷目前的 token
+// @Produce json
+// @Accept json
+// @Tags Token
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Param Body body apireq.LogoutSysAccountToken false "Request Logout Sys Account Token"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500005","message":"Redis server error"}"
+// @Router /v1/token/logout [post]
+func Logout(c *gin.Context) {
+	req := apireq.LogoutSysAccountToken{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil && err != io.EOF {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	claims, _ := c.Get("claims")
+	claimsMap := tokenLibrary.ParseClaims(claims)
+	exp, _ := claimsMap["exp"].(float64)
+	expiredAt := time.Unix(int64(exp), 0).UTC()
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+	err = ts.Logout(c.GetInt("account_id"), c.GetString("session_id"), c.GetString("token"), expiredAt, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// LogoutAll
+// @Summary Logout All 登出所有裝置
+// @Produce json
+// @Accept json
+// @Tags Token
+// @Security Bearer
+// @Param Authorization header string true "Bearer {JWT Token}"
+// @Success 200 {string} string "{}"
+// @Failure 401 {object} er.AppErrorMsg "{"code":"400401","message":"Unauthorized"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500005","message":"Redis server error"}"
+// @Router /v1/token/logout-all [post]
+func LogoutAll(c *gin.Context) {
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	llr := loginLogRepo.NewRepository(env.Orm)
+	tc := tokenRepo.NewRedis(env.RedisCluster)
+	ts := tokenSrv.NewService(sar, llr, tc)
+	err := ts.LogoutAll(c.GetInt("account_id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// GetJwks
+// @Summary Get JWKS 取得驗證 token 用的公開金鑰
+// @Produce json
+// @Tags Token
+// @Success 200 {object} apires.Jwks
+// @Router /.well-known/jwks.json [get]
+func GetJwks(c *gin.Context) {
+	c.JSON(http.StatusOK, tokenLibrary.GetJwks())
+}
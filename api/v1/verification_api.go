@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"net/http"
+	"oauth2-console-go/api"
+	"oauth2-console-go/dto/apireq"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	verificationRepo "oauth2-console-go/internal/system/verification/repository"
+	verificationSrv "oauth2-console-go/internal/system/verification/service"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfirmEmail
+// @Summary Confirm Email 以驗證信中的 token 完成 email 驗證
+// @Produce json
+// @Accept json
+// @Tags EmailVerification
+// @Param Body body apireq.ConfirmEmail true "Request Confirm Email"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500002","message":"Database update error"}"
+// @Router /v1/email-verification/confirm [post]
+func ConfirmEmail(c *gin.Context) {
+	req := apireq.ConfirmEmail{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	vc := verificationRepo.NewRedis(env.RedisCluster)
+	vs := verificationSrv.NewService(sar, vc, env.Mailer)
+
+	err = vs.ConfirmEmail(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
+
+// ResendVerification
+// @Summary Resend Verification 重新寄送 email 驗證信，每分鐘限寄一次，帳號不存在或已驗證時同樣回傳成功
+// @Produce json
+// @Accept json
+// @Tags EmailVerification
+// @Param Body body apireq.ResendVerification true "Request Resend Verification"
+// @Success 200 {string} string "{}"
+// @Failure 400 {object} er.AppErrorMsg "{"code":"400400","message":"Wrong parameter format or invalid"}"
+// @Failure 500 {object} er.AppErrorMsg "{"code":"500000","message":"Database unknown error"}"
+// @Router /v1/email-verification/resend [post]
+func ResendVerification(c *gin.Context) {
+	req := apireq.ResendVerification{}
+	err := c.BindJSON(&req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	// 參數驗證
+	err = valider.Validate.Struct(req)
+	if err != nil {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, err.Error(), err)
+		_ = c.Error(paramErr)
+		return
+	}
+
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	vc := verificationRepo.NewRedis(env.RedisCluster)
+	vs := verificationSrv.NewService(sar, vc, env.Mailer)
+
+	err = vs.ResendVerification(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{})
+}
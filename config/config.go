@@ -5,6 +5,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +20,31 @@ const (
 	EnvLocalhost           = "localhost"
 	AdminUserId            = int64(-1)
 	RedisDefaultExpireTime = time.Second * 60 * 60 * 24 * 30 // 預設一個月
+	RefreshTokenExpireTime = time.Hour * 24 * 30             // refresh token 有效期限一個月
+	MfaChallengeExpireTime = time.Minute * 5                 // 兩步驟驗證的 mfa token 有效期限
+	MfaChallengeMaxAttempt = 5                               // mfa token 可嘗試驗證的次數
+	LoginFailureWindow     = time.Minute * 15                // 登入失敗次數的累計期間
+	LoginDelayThreshold    = 3                               // 同一帳號/IP 失敗超過此次數後，每次失敗延長等待時間
+	LoginMaxDelay          = time.Minute                     // 單次等待時間上限
+	LoginLockThreshold     = 10                              // 同一帳號失敗達此次數後暫時鎖定
+	LoginLockTime          = time.Minute * 15                // 帳號鎖定時間
+	LoginIpLockThreshold   = 50                              // 同一 IP 失敗達此次數後暫時拒絕登入
+	ApiKeyMaxCount         = 20                              // 每個帳號可建立的 api key 數量
+	RedirectUriMaxCount    = 20                              // 每個 client 可登記的 redirect uri 數量
+	ForgotPassExpireTime   = time.Minute * 30                // 重設密碼連結有效期限
+	ForgotPassResendTime   = time.Minute                     // 重設密碼信件重寄間隔
+	EmailVerifyExpireTime  = time.Hour * 24                  // email 驗證連結有效期限
+	EmailVerifyResendTime  = time.Minute                     // email 驗證信件重寄間隔
+	InvitationExpireTime   = time.Hour * 72                  // 邀請連結有效期限
+	ClientSecretGraceTime  = time.Hour * 24                  // 輪替 client secret 後，舊 secret 的預設寬限期
+	AccountEnableCacheTime = time.Minute * 5                 // 帳號未停用的快取時間，直接於資料庫停用時最多延遲此時間生效
+	JwtLegacyIssuer        = "address-book-go"               // 舊版 HS384 token 的 iss，未設定 JWT_ISSUER 時沿用
+	MailDriverSmtp         = "smtp"
+	MailDriverOutbox       = "outbox"
+	StorageDriverLocal     = "local"
+	StorageDriverS3        = "s3"
+	StorageLocalRoute      = "/storage" // 本機儲存的靜態檔案路由
+	ClientIconSizeLimit    = 2          // client icon 上傳大小限制(MB)
 )
 
 var EnvShortName = map[string]string{
@@ -42,6 +68,169 @@ func GetJwtSalt() string {
 	return os.Getenv("JWT_SALT")
 }
 
+// Jwt issuer，驗證 token 時 iss 必須相符
+func GetJwtIssuer() string {
+	iss := os.Getenv("JWT_ISSUER")
+	if iss == "" {
+		return JwtLegacyIssuer
+	}
+	return iss
+}
+
+// 遷移期間是否接受 iss 為 address-book-go 的舊版 HS384 token，預設關閉
+func GetJwtAcceptLegacyIssuer() bool {
+	return os.Getenv("JWT_ACCEPT_LEGACY_ISSUER") == "true"
+}
+
+// Jwt audience，有設定時 token 必須帶有相同的 aud
+func GetJwtAudience() string {
+	return os.Getenv("JWT_AUDIENCE")
+}
+
+// Jwt lifetime，格式為 time.ParseDuration，預設 24 小時
+func GetJwtLifetime() time.Duration {
+	lifetime, err := time.ParseDuration(os.Getenv("JWT_LIFETIME"))
+	if err != nil || lifetime <= 0 {
+		return time.Hour * 24
+	}
+	return lifetime
+}
+
+// Mfa issuer，顯示於 authenticator app 的服務名稱，預設同 jwt issuer
+func GetMfaIssuer() string {
+	iss := os.Getenv("MFA_ISSUER")
+	if iss == "" {
+		return GetJwtIssuer()
+	}
+	return iss
+}
+
+// Jwt asymmetric signing key (PEM), 未設定時使用 JWT_SALT 簽章
+func GetJwtSigningKeyPath() string {
+	return os.Getenv("JWT_SIGNING_KEY")
+}
+
+// Jwt retired keys (PEM)，只用於驗證舊的 token，以逗號分隔
+func GetJwtVerifyingKeyPaths() []string {
+	paths := make([]string, 0)
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFYING_KEYS"), ",") {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Mail driver，smtp 或 outbox(不寄出，寫入 MAIL_OUTBOX_DIR)，預設 outbox
+func GetMailDriver() string {
+	driver := os.Getenv("MAIL_DRIVER")
+	if driver == "" {
+		return MailDriverOutbox
+	}
+	return driver
+}
+
+func GetMailFrom() string {
+	return os.Getenv("MAIL_FROM")
+}
+
+func GetMailOutboxDir() string {
+	return os.Getenv("MAIL_OUTBOX_DIR")
+}
+
+func GetSmtpHost() string {
+	return os.Getenv("SMTP_HOST")
+}
+
+// Smtp port，預設 587
+func GetSmtpPort() int {
+	port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	if err != nil || port <= 0 {
+		return 587
+	}
+	return port
+}
+
+func GetSmtpUsername() string {
+	return os.Getenv("SMTP_USERNAME")
+}
+
+func GetSmtpPassword() string {
+	return os.Getenv("SMTP_PASSWORD")
+}
+
+// Storage driver，local 或 s3(相容 S3 API 的物件儲存)，預設 local
+func GetStorageDriver() string {
+	driver := os.Getenv("STORAGE_DRIVER")
+	if driver == "" {
+		return StorageDriverLocal
+	}
+	return driver
+}
+
+// 本機儲存目錄，預設 storage/public
+func GetStorageLocalDir() string {
+	dir := os.Getenv("STORAGE_LOCAL_DIR")
+	if dir == "" {
+		return "storage/public"
+	}
+	return dir
+}
+
+// 本機儲存檔案的公開網址，預設為同一台 api server 的 /storage
+func GetStoragePublicUrl() string {
+	u := strings.TrimRight(os.Getenv("STORAGE_PUBLIC_URL"), "/")
+	if u == "" {
+		return StorageLocalRoute
+	}
+	return u
+}
+
+func GetS3Endpoint() string {
+	return os.Getenv("S3_ENDPOINT")
+}
+
+func GetS3Region() string {
+	return os.Getenv("S3_REGION")
+}
+
+func GetS3Bucket() string {
+	return os.Getenv("S3_BUCKET")
+}
+
+func GetS3AccessKey() string {
+	return os.Getenv("S3_ACCESS_KEY")
+}
+
+func GetS3SecretKey() string {
+	return os.Getenv("S3_SECRET_KEY")
+}
+
+// MinIO 等相容服務使用 path style(endpoint/bucket/key)
+func GetS3PathStyle() bool {
+	return os.Getenv("S3_PATH_STYLE") == "true"
+}
+
+// S3 簽章網址有效期限，格式為 time.ParseDuration，預設 1 小時
+func GetS3UrlExpire() time.Duration {
+	expire, err := time.ParseDuration(os.Getenv("S3_URL_EXPIRE"))
+	if err != nil || expire <= 0 {
+		return time.Hour
+	}
+	return expire
+}
+
+// 是否拒絕尚未驗證 email 的帳號登入，預設關閉
+func GetRequireVerifiedLogin() bool {
+	return os.Getenv("REQUIRE_VERIFIED_LOGIN") == "true"
+}
+
+// Console 前端網址，用於信件中的連結
+func GetConsoleUrl() string {
+	return strings.TrimRight(os.Getenv("CONSOLE_URL"), "/")
+}
+
 // Base path
 var (
 	_, b, _, _ = runtime.Caller(0)
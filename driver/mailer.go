@@ -0,0 +1,30 @@
+package driver
+
+import (
+	"fmt"
+	"oauth2-console-go/config"
+	"oauth2-console-go/pkg/mailer"
+	"path/filepath"
+)
+
+func NewMailer() (mailer.Mailer, error) {
+	switch config.GetMailDriver() {
+	case config.MailDriverSmtp:
+		if config.GetSmtpHost() == "" {
+			return nil, fmt.Errorf("smtp host is required")
+		}
+		return mailer.NewSmtpMailer(config.GetSmtpHost(), config.GetSmtpPort(), config.GetSmtpUsername(), config.GetSmtpPassword(), config.GetMailFrom()), nil
+	case config.MailDriverOutbox:
+		// outbox 不會寄出郵件，正式環境須設定 smtp
+		if config.GetEnvironment() == config.EnvProduction {
+			return nil, fmt.Errorf("mail driver outbox is not allowed in production")
+		}
+		dir := config.GetMailOutboxDir()
+		if dir != "" && !filepath.IsAbs(dir) {
+			dir = filepath.Join(config.GetBasePath(), dir)
+		}
+		return mailer.NewOutbox(dir, config.GetMailFrom()), nil
+	default:
+		return nil, fmt.Errorf("mail driver %s not supported", config.GetMailDriver())
+	}
+}
@@ -0,0 +1,39 @@
+package driver
+
+import (
+	"fmt"
+	"oauth2-console-go/config"
+	"oauth2-console-go/pkg/storage"
+	"path/filepath"
+)
+
+func NewStorage() (storage.Storage, error) {
+	switch config.GetStorageDriver() {
+	case config.StorageDriverLocal:
+		return storage.NewLocal(GetStorageLocalDir(), config.GetStoragePublicUrl()), nil
+	case config.StorageDriverS3:
+		if config.GetS3Endpoint() == "" || config.GetS3Bucket() == "" {
+			return nil, fmt.Errorf("s3 endpoint and bucket are required")
+		}
+		return storage.NewS3(storage.S3Config{
+			Endpoint:  config.GetS3Endpoint(),
+			Region:    config.GetS3Region(),
+			Bucket:    config.GetS3Bucket(),
+			AccessKey: config.GetS3AccessKey(),
+			SecretKey: config.GetS3SecretKey(),
+			PathStyle: config.GetS3PathStyle(),
+			UrlExpire: config.GetS3UrlExpire(),
+		}), nil
+	default:
+		return nil, fmt.Errorf("storage driver %s not supported", config.GetStorageDriver())
+	}
+}
+
+// GetStorageLocalDir 相對路徑以專案根目錄為準
+func GetStorageLocalDir() string {
+	dir := config.GetStorageLocalDir()
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(config.GetBasePath(), dir)
+	}
+	return dir
+}
@@ -0,0 +1,15 @@
+package apireq
+
+import "time"
+
+type ListApiKey struct {
+	AccountId int `form:"account_id" validate:"required"`
+}
+
+type AddApiKey struct {
+	AccountId   int        `json:"account_id" validate:"required"`
+	Name        string     `json:"name" validate:"required,max=64"`
+	ReadOnly    bool       `json:"read_only"`
+	RouteGroups []string   `json:"route_groups" validate:"dive,oneof=oauth_client oauth_scope account"`
+	ExpiredAt   *time.Time `json:"expired_at"`
+}
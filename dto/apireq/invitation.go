@@ -0,0 +1,15 @@
+package apireq
+
+type AddInvitation struct {
+	AccountId int    `json:"account_id" validate:"required"`
+	Email     string `json:"email" validate:"required,email,max=64"`
+	Role      string `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+type AcceptInvitation struct {
+	Token    string `json:"token" validate:"required"`
+	Account  string `json:"account" validate:"required,max=64"`
+	Password string `json:"password" validate:"required,password"`
+	Name     string `json:"name" validate:"required,max=64"`
+	Phone    string `json:"phone" validate:"omitempty,max=20"`
+}
@@ -0,0 +1,8 @@
+package apireq
+
+type ListLoginLog struct {
+	AccountId    int `form:"account_id" validate:"required"`
+	SysAccountId int `form:"-"`
+	Page         int `form:"page" validate:"required"`
+	PerPage      int `form:"per_page" validate:"required"`
+}
@@ -0,0 +1,15 @@
+package apireq
+
+type EnrollMfaTotp struct {
+	AccountId int `json:"account_id" validate:"required"`
+}
+
+type ActivateMfaTotp struct {
+	AccountId int    `json:"account_id" validate:"required"`
+	Code      string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type DisableMfaTotp struct {
+	AccountId int    `json:"account_id" validate:"required"`
+	Code      string `json:"code" validate:"required"`
+}
@@ -3,18 +3,27 @@ package apireq
 import (
 	"mime/multipart"
 	"oauth2-console-go/dto/model"
+	"time"
 )
 
 type ListOauthClient struct {
-	AccountId int `form:"account_id" validate:"required"`
-	Page      int `form:"page" validate:"required"`
-	PerPage   int `form:"per_page" validate:"required"`
+	AccountId   int        `form:"account_id" validate:"required"`
+	Page        int        `form:"page" validate:"required"`
+	PerPage     int        `form:"per_page" validate:"required"`
+	Keyword     string     `form:"keyword" validate:"max=64"`
+	Domain      string     `form:"domain" validate:"max=255"`
+	OwnerId     int        `form:"owner_id" validate:"min=0"` // admin 以外只能查詢自己
+	Scope       string     `form:"scope" validate:"max=100"`
+	CreatedFrom *time.Time `form:"created_from"` // RFC3339
+	CreatedTo   *time.Time `form:"created_to"`
+	UpdatedFrom *time.Time `form:"updated_from"`
+	UpdatedTo   *time.Time `form:"updated_to"`
+	Sort        string     `form:"sort" validate:"max=128"` // e.g. name,-created_at
 }
 
 type AddOauthClient struct {
 	AccountId int    `form:"account_id" validate:"required"`
 	Id        string `form:"id" validate:"required"`
-	Secret    string `form:"secret" validate:"required"`
 	Domain    string `form:"domain" validate:"required"`
 	Name      string `form:"name" validate:"required"`
 }
@@ -28,11 +37,16 @@ type AddOauthClientWithFile struct {
 
 type EditOauthClient struct {
 	AccountId int    `form:"account_id" validate:"required"`
-	Secret    string `form:"secret" validate:"required"`
 	Domain    string `form:"domain" validate:"required"`
 	Name      string `form:"name" validate:"required"`
 	HasImage  *bool  `form:"has_image" validate:"required"`
 	ScopeList string `form:"scope_list" validate:"required"`
+
+	// 授權流程，未帶入時沿用原設定，帶入 grant_types 時 response_types 一併以帶入的值為準
+	GrantTypes    []string `form:"grant_types" validate:"omitempty,dive,oneof=authorization_code client_credentials refresh_token device_code"`
+	ResponseTypes []string `form:"response_types" validate:"omitempty,dive,oneof=code token"`
+	PkceRequired  *bool    `form:"pkce_required"`
+	PkceS256Only  *bool    `form:"pkce_s256_only"`
 }
 
 type EditOauthClientWithFile struct {
@@ -42,3 +56,18 @@ type EditOauthClientWithFile struct {
 	FileExtension string
 	ScopeList     *model.ScopeList
 }
+
+type RotateOauthClientSecret struct {
+	AccountId  int  `json:"account_id" validate:"required"`
+	GraceHours *int `json:"grace_hours" validate:"omitempty,min=0,max=720"`
+}
+
+type AddOauthClientRedirectUri struct {
+	AccountId   int    `json:"account_id" validate:"required"`
+	RedirectUri string `json:"redirect_uri" validate:"required,max=512"`
+}
+
+type TransferOauthClient struct {
+	AccountId   int `json:"account_id" validate:"required"`
+	ToAccountId int `json:"to_account_id" validate:"required"`
+}
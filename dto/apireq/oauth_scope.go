@@ -1,9 +1,20 @@
 package apireq
 
+import "time"
+
 type ListOauthScope struct {
-	AccountId int `form:"account_id" validate:"required"`
-	Page      int `form:"page" validate:"required"`
-	PerPage   int `form:"per_page" validate:"required"`
+	AccountId   int        `form:"account_id" validate:"required"`
+	Page        int        `form:"page" validate:"required"`
+	PerPage     int        `form:"per_page" validate:"required"`
+	Keyword     string     `form:"keyword" validate:"max=100"`
+	Method      string     `form:"method" validate:"omitempty,oneof=GET POST PUT PATCH DELETE"`
+	IsDisable   *bool      `form:"is_disable"`
+	Scope       string     `form:"scope" validate:"max=100"`
+	CreatedFrom *time.Time `form:"created_from"` // RFC3339
+	CreatedTo   *time.Time `form:"created_to"`
+	UpdatedFrom *time.Time `form:"updated_from"`
+	UpdatedTo   *time.Time `form:"updated_to"`
+	Sort        string     `form:"sort" validate:"max=128"` // e.g. scope,-created_at
 }
 
 type AddOauthScope struct {
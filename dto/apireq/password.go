@@ -0,0 +1,10 @@
+package apireq
+
+type ForgotPassword struct {
+	Email string `json:"email" validate:"required,email,max=64"`
+}
+
+type ResetPassword struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,password"`
+}
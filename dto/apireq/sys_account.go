@@ -0,0 +1,48 @@
+package apireq
+
+type ListSysAccount struct {
+	AccountId int `form:"account_id" validate:"required"`
+	Page      int `form:"page" validate:"required"`
+	PerPage   int `form:"per_page" validate:"required"`
+}
+
+type AddSysAccount struct {
+	AccountId   int    `json:"account_id" validate:"required"`
+	Account     string `json:"account" validate:"required,max=64"`
+	Password    string `json:"password" validate:"required,password"`
+	Name        string `json:"name" validate:"required,max=64"`
+	Email       string `json:"email" validate:"required,email,max=64"`
+	Phone       string `json:"phone" validate:"omitempty,max=20"`
+	Role        string `json:"role" validate:"required,oneof=admin editor viewer"`
+	MfaRequired bool   `json:"mfa_required"`
+}
+
+type EditSysAccount struct {
+	AccountId   int    `json:"account_id" validate:"required"`
+	Name        string `json:"name" validate:"required,max=64"`
+	Email       string `json:"email" validate:"required,email,max=64"`
+	Phone       string `json:"phone" validate:"omitempty,max=20"`
+	Role        string `json:"role" validate:"required,oneof=admin editor viewer"`
+	MfaRequired *bool  `json:"mfa_required" validate:"required"`
+}
+
+type EditProfile struct {
+	Name  string `json:"name" validate:"required,max=64"`
+	Email string `json:"email" validate:"required,email,max=64"`
+	Phone string `json:"phone" validate:"omitempty,max=20"`
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,password,nefield=CurrentPassword"`
+	UserAgent       string `json:"-"`
+	Ip              string `json:"-"`
+}
+
+type ConfirmEmail struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ResendVerification struct {
+	Email string `json:"email" validate:"required,email,max=64"`
+}
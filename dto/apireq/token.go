@@ -1,6 +1,27 @@
 package apireq
 
 type GetSysAccountToken struct {
-	Account  string `json:"account" validate:"required"`
-	Password string `json:"password" validate:"required"`
+	Account   string `json:"account" validate:"required"`
+	Password  string `json:"password" validate:"required"`
+	UserAgent string `json:"-"`
+	Ip        string `json:"-"`
+}
+
+type RefreshSysAccountToken struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutSysAccountToken struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ListSysAccountSession struct {
+	AccountId int `form:"account_id" validate:"required"`
+}
+
+type VerifySysAccountMfa struct {
+	MfaToken  string `json:"mfa_token" validate:"required"`
+	Code      string `json:"code" validate:"required"`
+	UserAgent string `json:"-"`
+	Ip        string `json:"-"`
 }
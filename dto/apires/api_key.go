@@ -0,0 +1,24 @@
+package apires
+
+import "time"
+
+type ListApiKey struct {
+	List []*ApiKey `json:"list"`
+}
+
+type ApiKey struct {
+	Id          int        `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	ReadOnly    bool       `json:"read_only"`
+	RouteGroups []string   `json:"route_groups"`
+	ExpiredAt   *time.Time `json:"expired_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIp  string     `json:"last_used_ip"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type AddApiKey struct {
+	*ApiKey
+	Key string `json:"key"` // 僅於建立時回傳一次
+}
@@ -0,0 +1,9 @@
+package apires
+
+import "time"
+
+type Invitation struct {
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
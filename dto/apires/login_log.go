@@ -0,0 +1,10 @@
+package apires
+
+import "oauth2-console-go/dto/model"
+
+type ListLoginLog struct {
+	List        []*model.SysAccountLoginLog `json:"list"`
+	CurrentPage int                         `json:"current_page"`
+	PerPage     int                         `json:"per_page"`
+	Total       int                         `json:"total"`
+}
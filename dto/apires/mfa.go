@@ -0,0 +1,7 @@
+package apires
+
+type MfaTotpEnroll struct {
+	Secret        string   `json:"secret"`
+	KeyUri        string   `json:"key_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -13,23 +13,52 @@ type ListOauthClient struct {
 }
 
 type ListOauthClientItem struct {
-	Id        string    `xorm:"not null pk VARCHAR(255)" json:"id"`
-	Secret    string    `xorm:"not null VARCHAR(255)" json:"secret"`
-	Domain    string    `xorm:"not null VARCHAR(255)" json:"domain"`
-	Name      string    `xorm:"not null VARCHAR(255)" json:"name"`
-	CreatedAt time.Time `xorm:"created" json:"created_at"`
-	UpdatedAt time.Time `xorm:"updated" json:"updated_at"`
+	Id           string    `xorm:"not null pk VARCHAR(255)" json:"id"`
+	SysAccountId int       `xorm:"not null INT" json:"sys_account_id"`
+	Domain       string    `xorm:"not null VARCHAR(255)" json:"domain"`
+	Name         string    `xorm:"not null VARCHAR(255)" json:"name"`
+	IconPath     string    `xorm:"not null VARCHAR(191)" json:"icon_path"`
+	IconUrl      string    `xorm:"-" json:"icon_url"`
+	CreatedAt    time.Time `xorm:"created" json:"created_at"`
+	UpdatedAt    time.Time `xorm:"updated" json:"updated_at"`
 }
 
 type OauthClient struct {
-	Id           string           `xorm:"not null pk VARCHAR(255)" json:"id"`
-	SysAccountId int              `xorm:"not null INT" json:"sys_account_id"`
-	Name         string           `xorm:"not null VARCHAR(255)" json:"name"`
-	Secret       string           `xorm:"not null VARCHAR(255)" json:"secret"`
-	Domain       string           `xorm:"not null VARCHAR(255)" json:"domain"`
-	Scope        string           `xorm:"not null VARCHAR(255)" json:"scope"`
-	IconPath     string           `xorm:"not null VARCHAR(191)" json:"icon_path"`
-	ScopeList    *model.ScopeList `json:"scope_list"`
-	CreatedAt    time.Time        `xorm:"created" json:"created_at"`
-	UpdatedAt    time.Time        `xorm:"updated" json:"updated_at"`
+	Id            string                          `xorm:"not null pk VARCHAR(255)" json:"id"`
+	SysAccountId  int                             `xorm:"not null INT" json:"sys_account_id"`
+	Name          string                          `xorm:"not null VARCHAR(255)" json:"name"`
+	Domain        string                          `xorm:"not null VARCHAR(255)" json:"domain"`
+	Scope         string                          `xorm:"not null VARCHAR(255)" json:"scope"`
+	IconPath      string                          `xorm:"not null VARCHAR(191)" json:"icon_path"`
+	IconUrl       string                          `xorm:"-" json:"icon_url"`
+	GrantTypes    []string                        `json:"grant_types"`
+	ResponseTypes []string                        `json:"response_types"`
+	PkceRequired  bool                            `json:"pkce_required"`
+	PkceS256Only  bool                            `json:"pkce_s256_only"`
+	ScopeList     *model.ScopeList                `json:"scope_list"`
+	RedirectUris  []*model.OauthClientRedirectUri `json:"redirect_uris"`
+	CreatedAt     time.Time                       `xorm:"created" json:"created_at"`
+	UpdatedAt     time.Time                       `xorm:"updated" json:"updated_at"`
+}
+
+// OauthClientSecret 明文 secret 只在建立或輪替時回傳一次
+type OauthClientSecret struct {
+	ClientId                string     `json:"client_id"`
+	Secret                  string     `json:"secret"`
+	PreviousSecretExpiredAt *time.Time `json:"previous_secret_expired_at"`
+}
+
+type ListOauthClientSecret struct {
+	List []*OauthClientSecretItem `json:"list"`
+}
+
+// OauthClientSecretItem 不回傳 secret，Type 為 current 或 previous
+type OauthClientSecretItem struct {
+	Type      string     `json:"type"`
+	CreatedAt *time.Time `json:"created_at"`
+	ExpiredAt *time.Time `json:"expired_at"`
+}
+
+type ListOauthClientRedirectUri struct {
+	List []*model.OauthClientRedirectUri `json:"list"`
 }
@@ -0,0 +1,51 @@
+package apires
+
+import (
+	"oauth2-console-go/dto/model"
+	"time"
+)
+
+type ListSysAccount struct {
+	List        []*SysAccount `json:"list"`
+	CurrentPage int           `json:"current_page"`
+	PerPage     int           `json:"per_page"`
+	Total       int           `json:"total"`
+}
+
+type SysAccount struct {
+	Id          int        `json:"id"`
+	Account     string     `json:"account"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Phone       string     `json:"phone"`
+	Role        string     `json:"role"`
+	IsDisable   bool       `json:"is_disable"`
+	VerifyAt    *time.Time `json:"verify_at"`
+	MfaEnabled  bool       `json:"mfa_enabled"`
+	MfaRequired bool       `json:"mfa_required"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// NewSysAccount 轉換為回應格式，不回傳密碼、TOTP secret 等欄位
+func NewSysAccount(m *model.SysAccount) *SysAccount {
+	res := SysAccount{
+		Id:          m.Id,
+		Account:     m.Account,
+		Name:        m.Name,
+		Email:       m.Email,
+		Phone:       m.Phone,
+		Role:        m.Role,
+		IsDisable:   m.IsDisable,
+		MfaEnabled:  m.MfaEnabled,
+		MfaRequired: m.MfaRequired,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+	if !m.VerifyAt.IsZero() {
+		verifyAt := m.VerifyAt
+		res.VerifyAt = &verifyAt
+	}
+
+	return &res
+}
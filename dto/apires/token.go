@@ -3,7 +3,42 @@ package apires
 import "time"
 
 type SysAccountToken struct {
-	Token     string                 `json:"token"`
-	ExpiredAt time.Time              `json:"expired_at"`
-	Data      map[string]interface{} `json:"data"`
+	Token                 string                 `json:"token"`
+	ExpiredAt             time.Time              `json:"expired_at"`
+	RefreshToken          string                 `json:"refresh_token"`
+	RefreshTokenExpiredAt time.Time              `json:"refresh_token_expired_at"`
+	MfaRequired           bool                   `json:"mfa_required,omitempty"`
+	MfaToken              string                 `json:"mfa_token,omitempty"`
+	MfaTokenExpiredAt     *time.Time             `json:"mfa_token_expired_at,omitempty"`
+	Data                  map[string]interface{} `json:"data"`
+}
+
+type ListSysAccountSession struct {
+	List []*SysAccountSession `json:"list"`
+}
+
+type SysAccountSession struct {
+	Id         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	Ip         string    `json:"ip"`
+	IssuedAt   time.Time `json:"issued_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
+
+type Jwks struct {
+	Keys []*JwksKey `json:"keys"`
+}
+
+type JwksKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
 }
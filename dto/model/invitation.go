@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type Invitation struct {
+	TokenHash string    `json:"token_hash"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy int       `json:"invited_by"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
@@ -2,17 +2,47 @@ package model
 
 import "time"
 
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeDeviceCode        = "device_code"
+
+	ResponseTypeCode  = "code"
+	ResponseTypeToken = "token" // implicit
+
+	DefaultGrantTypes    = GrantTypeAuthorizationCode + " " + GrantTypeRefreshToken
+	DefaultResponseTypes = ResponseTypeCode
+)
+
 type OauthClient struct {
-	Id           string    `xorm:"not null default '' comment('id') VARCHAR(255)" json:"id"`
-	SysAccountId int       `xorm:"not null default '' comment('sys_account_id') VARCHAR(255)" json:"sys_account_id"`
-	Name         string    `xorm:"not null default '' comment('name') VARCHAR(255)" json:"name"`
-	Secret       string    `xorm:"not null default '' comment('secret') VARCHAR(255)" json:"secret"`
-	Domain       string    `xorm:"not null default '' comment('domain') VARCHAR(255)" json:"domain"`
-	Scope        string    `xorm:"not null default '' comment('scope') VARCHAR(255)" json:"scope"`
-	IconPath     string    `xorm:"not null default '' comment('icon_path') VARCHAR(191)" json:"icon_path"`
-	Data         string    `xorm:"not null default '' comment('data') TEXT" json:"data"`
-	CreatedAt    time.Time `xorm:"not null created DATETIME" json:"created_at"`
-	UpdatedAt    time.Time `xorm:"not null updated DATETIME" json:"updated_at"`
+	Id                      string    `xorm:"not null default '' comment('id') VARCHAR(255)" json:"id"`
+	SysAccountId            int       `xorm:"not null default '' comment('sys_account_id') VARCHAR(255)" json:"sys_account_id"`
+	Name                    string    `xorm:"not null default '' comment('name') VARCHAR(255)" json:"name"`
+	Secret                  string    `xorm:"not null default '' comment('secret') VARCHAR(255)" json:"secret"`
+	SecretCreatedAt         time.Time `xorm:"comment('secret_created_at') DATETIME" json:"secret_created_at"`
+	PreviousSecret          string    `xorm:"not null default '' comment('previous_secret') VARCHAR(255)" json:"previous_secret"`
+	PreviousSecretCreatedAt time.Time `xorm:"comment('previous_secret_created_at') DATETIME" json:"previous_secret_created_at"`
+	PreviousSecretExpiredAt time.Time `xorm:"comment('previous_secret_expired_at') DATETIME" json:"previous_secret_expired_at"`
+	Domain                  string    `xorm:"not null default '' comment('domain') VARCHAR(255)" json:"domain"`
+	Scope                   string    `xorm:"not null default '' comment('scope') VARCHAR(255)" json:"scope"`
+	IconPath                string    `xorm:"not null default '' comment('icon_path') VARCHAR(191)" json:"icon_path"`
+	GrantTypes              string    `xorm:"not null default '' comment('grant_types') VARCHAR(255)" json:"grant_types"`      // 以空白分隔
+	ResponseTypes           string    `xorm:"not null default '' comment('response_types') VARCHAR(64)" json:"response_types"` // 以空白分隔
+	PkceRequired            bool      `xorm:"not null pkce_required" json:"pkce_required"`
+	PkceS256Only            bool      `xorm:"not null pkce_s256_only" json:"pkce_s256_only"`
+	RedirectUris            []string  `xorm:"-" json:"redirect_uris"` // 只存在於 data，由 oauth_client_redirect_uri 產生
+	Data                    string    `xorm:"not null default '' comment('data') TEXT" json:"data"`
+	CreatedAt               time.Time `xorm:"not null created DATETIME" json:"created_at"`
+	UpdatedAt               time.Time `xorm:"not null updated DATETIME" json:"updated_at"`
+}
+
+type OauthClientRedirectUri struct {
+	Id            int       `xorm:"pk autoincr BIGINT(20)" json:"id"`
+	OauthClientId string    `xorm:"not null comment('oauth_client_id') VARCHAR(255)" json:"oauth_client_id"`
+	RedirectUri   string    `xorm:"not null default '' comment('redirect_uri') VARCHAR(512)" json:"redirect_uri"`
+	CreatedAt     time.Time `xorm:"not null created DATETIME" json:"created_at"`
+	UpdatedAt     time.Time `xorm:"not null updated DATETIME" json:"updated_at"`
 }
 
 type OauthClientRedisCache struct {
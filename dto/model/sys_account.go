@@ -2,17 +2,36 @@ package model
 
 import "time"
 
+const (
+	RoleAdmin  = "admin"  // 所有功能，含帳號及 Oauth Scope 管理
+	RoleEditor = "editor" // 可編輯 Oauth Client
+	RoleViewer = "viewer" // 唯讀
+)
+
 type SysAccount struct {
 	Id                       int       `xorm:"pk autoincr BIGINT(20)" json:"id"`
 	Account                  string    `xorm:"not null default '' comment('account') VARCHAR(64)" json:"account"`
 	Phone                    string    `xorm:"not null default '' comment('phone') VARCHAR(20)" json:"phone"`
 	Email                    string    `xorm:"not null default '' comment('email') VARCHAR(64)" json:"email"`
-	Password                 string    `xorm:"not null default '' comment('password') VARCHAR(64)" json:"password"`
+	Password                 string    `xorm:"not null default '' comment('password') VARCHAR(255)" json:"-"`
 	Name                     string    `xorm:"not null default '' comment('name') VARCHAR(64)" json:"name"`
+	Role                     string    `xorm:"not null default 'viewer' comment('role') VARCHAR(16)" json:"role"`
 	IsDisable                bool      `xorm:"not null is_disable" json:"is_disable"`
 	VerifyAt                 time.Time `xorm:"comment('verify_at') DATETIME" json:"verify_at"`
-	ForgotPassToken          string    `xorm:"default '' comment('forgot_pass_token') VARCHAR(64)" json:"forgot_pass_token"`
-	ForgotPassTokenExpiredAt time.Time `xorm:"comment('forgot_pass_token_expired_at') DATETIME" json:"forgot_pass_token_expired_at"`
+	ForgotPassToken          string    `xorm:"default '' comment('forgot_pass_token') VARCHAR(64)" json:"-"`
+	ForgotPassTokenExpiredAt time.Time `xorm:"comment('forgot_pass_token_expired_at') DATETIME" json:"-"`
+	TotpSecret               string    `xorm:"not null default '' comment('totp_secret') VARCHAR(64)" json:"-"`
+	TotpLastCounter          int64     `xorm:"not null default 0 comment('totp_last_counter') BIGINT" json:"-"`
+	MfaRecoveryCodes         string    `xorm:"not null default '' comment('mfa_recovery_codes') TEXT" json:"-"`
+	MfaEnabled               bool      `xorm:"not null mfa_enabled" json:"mfa_enabled"`
+	MfaRequired              bool      `xorm:"not null mfa_required" json:"mfa_required"`
 	CreatedAt                time.Time `xorm:"not null created DATETIME" json:"created_at"`
 	UpdatedAt                time.Time `xorm:"not null updated DATETIME" json:"updated_at"`
 }
+
+type EmailVerification struct {
+	TokenHash string    `json:"token_hash"`
+	AccountId int       `json:"account_id"`
+	Email     string    `json:"email"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type SysAccountApiKey struct {
+	Id           int       `xorm:"pk autoincr BIGINT(20)" json:"id"`
+	SysAccountId int       `xorm:"not null comment('sys_account_id') BIGINT(20)" json:"sys_account_id"`
+	Name         string    `xorm:"not null default '' comment('name') VARCHAR(64)" json:"name"`
+	Prefix       string    `xorm:"not null default '' comment('prefix') VARCHAR(16)" json:"prefix"`
+	KeyHash      string    `xorm:"not null default '' comment('key_hash') VARCHAR(64)" json:"-"`
+	ReadOnly     bool      `xorm:"not null read_only" json:"read_only"`
+	RouteGroups  string    `xorm:"not null default '' comment('route_groups') VARCHAR(255)" json:"route_groups"`
+	ExpiredAt    time.Time `xorm:"comment('expired_at') DATETIME" json:"expired_at"`
+	LastUsedAt   time.Time `xorm:"comment('last_used_at') DATETIME" json:"last_used_at"`
+	LastUsedIp   string    `xorm:"not null default '' comment('last_used_ip') VARCHAR(64)" json:"last_used_ip"`
+	CreatedAt    time.Time `xorm:"not null created DATETIME" json:"created_at"`
+	UpdatedAt    time.Time `xorm:"not null updated DATETIME" json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// 登入失敗原因
+const (
+	LoginReasonAccountNotFound  = "account_not_found"
+	LoginReasonAccountDisabled  = "account_disabled"
+	LoginReasonPasswordMismatch = "password_mismatch"
+	LoginReasonAccountLocked    = "account_locked"
+	LoginReasonTooManyAttempts  = "too_many_attempts"
+	LoginReasonMfaFailed        = "mfa_failed"
+	LoginReasonUnverified       = "unverified"
+)
+
+type SysAccountLoginLog struct {
+	Id           int       `xorm:"pk autoincr BIGINT(20)" json:"id"`
+	SysAccountId int       `xorm:"not null default 0 comment('sys_account_id') BIGINT(20)" json:"sys_account_id"`
+	Account      string    `xorm:"not null default '' comment('account') VARCHAR(64)" json:"account"`
+	Ip           string    `xorm:"not null default '' comment('ip') VARCHAR(64)" json:"ip"`
+	UserAgent    string    `xorm:"not null default '' comment('user_agent') VARCHAR(255)" json:"user_agent"`
+	IsSuccess    bool      `xorm:"not null is_success" json:"is_success"`
+	Reason       string    `xorm:"not null default '' comment('reason') VARCHAR(64)" json:"reason"`
+	CreatedAt    time.Time `xorm:"not null created DATETIME" json:"created_at"`
+}
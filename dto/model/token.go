@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type RefreshToken struct {
+	FamilyId  string    `json:"family_id"`
+	TokenHash string    `json:"token_hash"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+type Session struct {
+	Id         string    `json:"id"`
+	AccountId  int       `json:"account_id"`
+	UserAgent  string    `json:"user_agent"`
+	Ip         string    `json:"ip"`
+	IssuedAt   time.Time `json:"issued_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
+}
+
+type MfaChallenge struct {
+	Id        string    `json:"id"`
+	AccountId int       `json:"account_id"`
+	UserAgent string    `json:"user_agent"`
+	Ip        string    `json:"ip"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+type LoginFailure struct {
+	Count        int       `json:"count"`
+	LastFailedAt time.Time `json:"last_failed_at"`
+}
@@ -4,14 +4,43 @@ import (
 	"fmt"
 	"oauth2-console-go/dto/apires"
 	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/helper"
+	"time"
 )
 
 type Repository interface {
-	Count() (int, error)
-	Find(limit, offset int) ([]*apires.ListOauthClientItem, error)
+	Count(filter *ListFilter) (int, error)
+	Find(filter *ListFilter, limit, offset int) ([]*apires.ListOauthClientItem, error)
 	FindOne(client *model.OauthClient) (*model.OauthClient, error)
 	Insert(info *model.OauthClient) error
 	Update(info *model.OauthClient) error
+	Delete(clientId string) error
+	FindRedirectUri(clientId string) ([]*model.OauthClientRedirectUri, error)
+	InsertRedirectUri(m *model.OauthClientRedirectUri) error
+	DeleteRedirectUri(clientId string, redirectUriId int) (bool, error)
+}
+
+// ListFilter 列表的搜尋條件，零值的欄位不限制
+type ListFilter struct {
+	SysAccountId int    // 擁有者
+	Keyword      string // id 或 name 包含
+	Domain       string // domain 包含
+	Scope        string // 具有此授權，授權整個類別的 client 也符合
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	UpdatedFrom  *time.Time
+	UpdatedTo    *time.Time
+	Sort         []helper.SortField
+}
+
+// SortFields 可排序的欄位
+var SortFields = map[string]string{
+	"id":             "id",
+	"name":           "name",
+	"domain":         "domain",
+	"sys_account_id": "sys_account_id",
+	"created_at":     "created_at",
+	"updated_at":     "updated_at",
 }
 
 type Cache interface {
@@ -5,6 +5,8 @@ import (
 	"oauth2-console-go/dto/apires"
 	"oauth2-console-go/dto/model"
 	"oauth2-console-go/internal/oauth/client"
+	"oauth2-console-go/pkg/helper"
+	"strings"
 
 	"xorm.io/xorm"
 )
@@ -17,16 +19,32 @@ func NewRepository(orm *xorm.EngineGroup) client.Repository {
 	return &Repository{orm: orm}
 }
 
-func (r *Repository) Count() (int, error) {
-	cnt, err := r.orm.Count(&model.OauthClient{})
+func (r *Repository) Count(filter *client.ListFilter) (int, error) {
+	session := r.orm.Table("oauth_client")
+	defer session.Close()
+
+	cnt, err := applyFilter(session, filter).Count()
 	return int(cnt), err
 }
 
-func (r *Repository) Find(limit, offset int) ([]*apires.ListOauthClientItem, error) {
+func (r *Repository) Find(filter *client.ListFilter, limit, offset int) ([]*apires.ListOauthClientItem, error) {
 	var err error
 	clients := make([]*apires.ListOauthClientItem, 0)
 
-	err = r.orm.Table("oauth_client").Limit(limit, offset).Find(&clients)
+	session := r.orm.Table("oauth_client")
+	defer session.Close()
+
+	session = applyFilter(session, filter)
+	for _, field := range filter.Sort {
+		if field.Desc {
+			session = session.Desc(field.Column)
+		} else {
+			session = session.Asc(field.Column)
+		}
+	}
+
+	// 以 id 排序確保分頁結果穩定
+	err = session.Asc("id").Limit(limit, offset).Find(&clients)
 	if err != nil {
 		return nil, err
 	}
@@ -49,20 +67,27 @@ func (r *Repository) FindOne(client *model.OauthClient) (*model.OauthClient, err
 
 func (r *Repository) Insert(info *model.OauthClient) error {
 	oc := model.OauthClient{
-		Id:           info.Id,
-		SysAccountId: info.SysAccountId,
-		Name:         info.Name,
-		Secret:       info.Secret,
-		Domain:       info.Domain,
-		Scope:        info.Scope,
-		IconPath:     info.IconPath,
+		Id:                      info.Id,
+		SysAccountId:            info.SysAccountId,
+		Name:                    info.Name,
+		Secret:                  info.Secret,
+		SecretCreatedAt:         info.SecretCreatedAt,
+		PreviousSecret:          info.PreviousSecret,
+		PreviousSecretCreatedAt: info.PreviousSecretCreatedAt,
+		PreviousSecretExpiredAt: info.PreviousSecretExpiredAt,
+		Domain:                  info.Domain,
+		Scope:                   info.Scope,
+		IconPath:                info.IconPath,
+		GrantTypes:              info.GrantTypes,
+		ResponseTypes:           info.ResponseTypes,
+		PkceRequired:            info.PkceRequired,
+		PkceS256Only:            info.PkceS256Only,
 	}
 
-	jsonData, err := json.Marshal(oc)
+	err := r.marshalData(&oc)
 	if err != nil {
 		return err
 	}
-	oc.Data = string(jsonData)
 
 	_, err = r.orm.Insert(&oc)
 	if err != nil {
@@ -74,13 +99,101 @@ func (r *Repository) Insert(info *model.OauthClient) error {
 
 func (r *Repository) Update(info *model.OauthClient) error {
 	oc := model.OauthClient{
-		Id:           info.Id,
-		SysAccountId: info.SysAccountId,
-		Name:         info.Name,
-		Secret:       info.Secret,
-		Domain:       info.Domain,
-		Scope:        info.Scope,
-		IconPath:     info.IconPath,
+		Id:                      info.Id,
+		SysAccountId:            info.SysAccountId,
+		Name:                    info.Name,
+		Secret:                  info.Secret,
+		SecretCreatedAt:         info.SecretCreatedAt,
+		PreviousSecret:          info.PreviousSecret,
+		PreviousSecretCreatedAt: info.PreviousSecretCreatedAt,
+		PreviousSecretExpiredAt: info.PreviousSecretExpiredAt,
+		Domain:                  info.Domain,
+		Scope:                   info.Scope,
+		IconPath:                info.IconPath,
+		GrantTypes:              info.GrantTypes,
+		ResponseTypes:           info.ResponseTypes,
+		PkceRequired:            info.PkceRequired,
+		PkceS256Only:            info.PkceS256Only,
+	}
+
+	err := r.marshalData(&oc)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.orm.Where("id = ? ", oc.Id).Cols("sys_account_id", "name", "secret", "secret_created_at", "previous_secret", "previous_secret_created_at", "previous_secret_expired_at", "domain", "scope", "icon_path", "grant_types", "response_types", "pkce_required", "pkce_s256_only", "data").Update(oc)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(clientId string) error {
+	session := r.orm.NewSession()
+	defer session.Close()
+
+	err := session.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = session.Where("oauth_client_id = ? ", clientId).Delete(&model.OauthClientRedirectUri{})
+	if err != nil {
+		_ = session.Rollback()
+		return err
+	}
+
+	_, err = session.Where("id = ? ", clientId).Delete(&model.OauthClient{})
+	if err != nil {
+		_ = session.Rollback()
+		return err
+	}
+
+	return session.Commit()
+}
+
+func (r *Repository) FindRedirectUri(clientId string) ([]*model.OauthClientRedirectUri, error) {
+	list := make([]*model.OauthClientRedirectUri, 0)
+	err := r.orm.Where("oauth_client_id = ? ", clientId).Asc("id").Find(&list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (r *Repository) InsertRedirectUri(m *model.OauthClientRedirectUri) error {
+	_, err := r.orm.Insert(m)
+	if err != nil {
+		return err
+	}
+
+	return r.refreshData(m.OauthClientId)
+}
+
+func (r *Repository) DeleteRedirectUri(clientId string, redirectUriId int) (bool, error) {
+	affected, err := r.orm.Where("id = ? AND oauth_client_id = ? ", redirectUriId, clientId).Delete(&model.OauthClientRedirectUri{})
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	return true, r.refreshData(clientId)
+}
+
+// marshalData 產生授權伺服器讀取的 data，包含 redirect uri 列表
+func (r *Repository) marshalData(oc *model.OauthClient) error {
+	list, err := r.FindRedirectUri(oc.Id)
+	if err != nil {
+		return err
+	}
+
+	oc.RedirectUris = make([]string, 0, len(list))
+	for _, item := range list {
+		oc.RedirectUris = append(oc.RedirectUris, item.RedirectUri)
 	}
 
 	jsonData, err := json.Marshal(oc)
@@ -89,10 +202,59 @@ func (r *Repository) Update(info *model.OauthClient) error {
 	}
 	oc.Data = string(jsonData)
 
-	_, err = r.orm.Where("id = ? ", oc.Id).Cols("sys_account_id", "name", "secret", "domain", "scope", "icon_path", "data").Update(oc)
+	return nil
+}
+
+// refreshData redirect uri 異動後重新產生 client 的 data
+func (r *Repository) refreshData(clientId string) error {
+	oc := model.OauthClient{Id: clientId}
+	has, err := r.orm.Get(&oc)
 	if err != nil {
 		return err
 	}
+	if !has {
+		return nil
+	}
 
-	return nil
+	oc.Data = ""
+	err = r.marshalData(&oc)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.orm.Where("id = ? ", oc.Id).Cols("data").Update(&oc)
+	return err
+}
+
+func applyFilter(session *xorm.Session, filter *client.ListFilter) *xorm.Session {
+	if filter.SysAccountId > 0 {
+		session = session.Where("sys_account_id = ? ", filter.SysAccountId)
+	}
+	if filter.Keyword != "" {
+		keyword := "%" + helper.EscapeLike(filter.Keyword) + "%"
+		session = session.Where("(id LIKE ? OR name LIKE ?) ", keyword, keyword)
+	}
+	if filter.Domain != "" {
+		session = session.Where("domain LIKE ? ", "%"+helper.EscapeLike(filter.Domain)+"%")
+	}
+	if filter.Scope != "" {
+		// scope 以空白分隔，授權整個類別時 scope 只有類別名稱
+		category := strings.SplitN(filter.Scope, ".", 2)[0]
+		session = session.Where("(CONCAT(' ', scope, ' ') LIKE ? OR CONCAT(' ', scope, ' ') LIKE ?) ",
+			"% "+helper.EscapeLike(filter.Scope)+" %", "% "+helper.EscapeLike(category)+" %")
+	}
+	if filter.CreatedFrom != nil {
+		session = session.Where("created_at >= ? ", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		session = session.Where("created_at <= ? ", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		session = session.Where("updated_at >= ? ", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		session = session.Where("updated_at <= ? ", *filter.UpdatedTo)
+	}
+
+	return session
 }
@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"encoding/json"
 	"oauth2-console-go/config"
 	"oauth2-console-go/driver"
 	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/oauth/client"
+	"oauth2-console-go/pkg/helper"
 	"oauth2-console-go/pkg/valider"
 	"os"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
@@ -29,11 +33,16 @@ func TestRepository_Count(t *testing.T) {
 	ocr := NewRepository(orm)
 
 	// Act
-	total, err := ocr.Count()
+	total, err := ocr.Count(&client.ListFilter{})
 
 	// Assert
 	assert.Nil(t, err)
 	assert.Equal(t, 2, total)
+
+	// 只計算帳號擁有的 client
+	total, err = ocr.Count(&client.ListFilter{SysAccountId: 99999})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, total)
 }
 
 func TestRepository_Find(t *testing.T) {
@@ -42,12 +51,17 @@ func TestRepository_Find(t *testing.T) {
 	ocr := NewRepository(orm)
 
 	// Act
-	clients, err := ocr.Find(10, 0)
+	clients, err := ocr.Find(&client.ListFilter{}, 10, 0)
 
 	// Assert
 	assert.Nil(t, err)
 	assert.NotNil(t, clients)
 	assert.Len(t, clients, 2)
+
+	// 只列出帳號擁有的 client
+	clients, err = ocr.Find(&client.ListFilter{SysAccountId: 99999}, 10, 0)
+	assert.Nil(t, err)
+	assert.Len(t, clients, 0)
 }
 
 func TestRepository_FindOne(t *testing.T) {
@@ -119,3 +133,131 @@ func TestRepository_Update(t *testing.T) {
 	// Put back
 	_, _ = orm.Where("id = ?", client.Id).Update(client)
 }
+
+func TestRepository_Delete(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	ocr := NewRepository(orm)
+
+	info := model.OauthClient{
+		Id:           "test_delete_client",
+		SysAccountId: 1,
+		Name:         "Test Delete Client",
+		Secret:       "pa@@w0rd",
+		Domain:       "http://localhost:9088",
+	}
+	_ = ocr.Insert(&info)
+
+	// Act
+	err := ocr.Delete(info.Id)
+
+	// Assert
+	assert.Nil(t, err)
+	client, _ := ocr.FindOne(&model.OauthClient{Id: info.Id})
+	assert.Nil(t, client)
+}
+
+func TestRepository_RedirectUri(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	ocr := NewRepository(orm)
+
+	info := model.OauthClient{
+		Id:           "test_redirect_uri_client",
+		SysAccountId: 1,
+		Name:         "Test Redirect Uri Client",
+		Secret:       "pa@@w0rd",
+		Domain:       "https://localhost:9088",
+	}
+	_ = ocr.Insert(&info)
+
+	// Act
+	m := model.OauthClientRedirectUri{
+		OauthClientId: info.Id,
+		RedirectUri:   "https://example.com/callback",
+	}
+	err := ocr.InsertRedirectUri(&m)
+
+	// Assert
+	assert.Nil(t, err)
+	list, err := ocr.FindRedirectUri(info.Id)
+	assert.Nil(t, err)
+	assert.Len(t, list, 1)
+
+	// data 內包含 redirect uri 列表
+	client, _ := ocr.FindOne(&model.OauthClient{Id: info.Id})
+	data := model.OauthClient{}
+	_ = json.Unmarshal([]byte(client.Data), &data)
+	assert.Equal(t, []string{"https://example.com/callback"}, data.RedirectUris)
+
+	deleted, err := ocr.DeleteRedirectUri(info.Id, m.Id)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	client, _ = ocr.FindOne(&model.OauthClient{Id: info.Id})
+	data = model.OauthClient{}
+	_ = json.Unmarshal([]byte(client.Data), &data)
+	assert.Len(t, data.RedirectUris, 0)
+
+	deleted, err = ocr.DeleteRedirectUri(info.Id, m.Id)
+	assert.Nil(t, err)
+	assert.False(t, deleted)
+
+	// TearDown
+	_ = ocr.Delete(info.Id)
+}
+
+func TestRepository_FindFilter(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	ocr := NewRepository(orm)
+
+	clients := []model.OauthClient{
+		{Id: "test_filter_client_a", SysAccountId: 99998, Name: "Filter Alpha", Domain: "https://alpha.example.com", Scope: "user lifestyle.article.get"},
+		{Id: "test_filter_client_b", SysAccountId: 99998, Name: "Filter Beta_1", Domain: "https://beta.example.com", Scope: "lifestyle"},
+	}
+	for i := range clients {
+		_ = ocr.Insert(&clients[i])
+	}
+	now := time.Now()
+	hourAgo := now.Add(-time.Hour)
+	hourLater := now.Add(time.Hour)
+
+	testCases := []struct {
+		Name    string
+		Filter  client.ListFilter
+		WantIds []string
+	}{
+		{"owner", client.ListFilter{SysAccountId: 99998}, []string{"test_filter_client_a", "test_filter_client_b"}},
+		{"keyword name", client.ListFilter{SysAccountId: 99998, Keyword: "alpha"}, []string{"test_filter_client_a"}},
+		{"keyword id", client.ListFilter{SysAccountId: 99998, Keyword: "client_b"}, []string{"test_filter_client_b"}},
+		{"keyword escape", client.ListFilter{SysAccountId: 99998, Keyword: "a_1"}, []string{"test_filter_client_b"}},
+		{"domain", client.ListFilter{SysAccountId: 99998, Domain: "beta.example"}, []string{"test_filter_client_b"}},
+		{"scope", client.ListFilter{SysAccountId: 99998, Scope: "user"}, []string{"test_filter_client_a"}},
+		{"scope category", client.ListFilter{SysAccountId: 99998, Scope: "lifestyle.list.get"}, []string{"test_filter_client_b"}},
+		{"scope item", client.ListFilter{SysAccountId: 99998, Scope: "lifestyle.article.get"}, []string{"test_filter_client_a", "test_filter_client_b"}},
+		{"created range", client.ListFilter{SysAccountId: 99998, CreatedFrom: &hourAgo, CreatedTo: &hourLater}, []string{"test_filter_client_a", "test_filter_client_b"}},
+		{"updated range", client.ListFilter{SysAccountId: 99998, UpdatedFrom: &hourLater}, []string{}},
+		{"sort desc", client.ListFilter{SysAccountId: 99998, Sort: []helper.SortField{{Column: "name", Desc: true}}}, []string{"test_filter_client_b", "test_filter_client_a"}},
+	}
+
+	for _, tc := range testCases {
+		// Act
+		list, err := ocr.Find(&tc.Filter, 10, 0)
+		total, countErr := ocr.Count(&tc.Filter)
+
+		// Assert
+		assert.Nil(t, err, tc.Name)
+		assert.Nil(t, countErr, tc.Name)
+		ids := make([]string, 0)
+		for _, item := range list {
+			ids = append(ids, item.Id)
+		}
+		assert.Equal(t, tc.WantIds, ids, tc.Name)
+		assert.Equal(t, len(tc.WantIds), total, tc.Name)
+	}
+
+	// TearDown
+	for _, c := range clients {
+		_ = ocr.Delete(c.Id)
+	}
+}
@@ -3,12 +3,21 @@ package client
 import (
 	"oauth2-console-go/dto/apireq"
 	"oauth2-console-go/dto/apires"
+	"oauth2-console-go/dto/model"
 	"oauth2-console-go/internal/oauth/scope"
 )
 
 type Service interface {
-	ListClient(sysAccId, page, perPage int) (*apires.ListOauthClient, error)
+	ListClient(req *apireq.ListOauthClient) (*apires.ListOauthClient, error)
 	GetClient(sysAccId int, clientId string, scopeRepo scope.Repository) (*apires.OauthClient, error)
-	AddClient(req *apireq.AddOauthClientWithFile) error
+	AddClient(req *apireq.AddOauthClientWithFile) (*apires.OauthClientSecret, error)
 	EditClient(clientId string, req *apireq.EditOauthClientWithFile, scopeRepo scope.Repository) error
+	DeleteClient(sysAccId int, clientId string) error
+	TransferClient(clientId string, req *apireq.TransferOauthClient) error
+	RotateSecret(clientId string, req *apireq.RotateOauthClientSecret) (*apires.OauthClientSecret, error)
+	ListSecret(sysAccId int, clientId string) (*apires.ListOauthClientSecret, error)
+	RevokePreviousSecret(sysAccId int, clientId string) error
+	ListRedirectUri(sysAccId int, clientId string) (*apires.ListOauthClientRedirectUri, error)
+	AddRedirectUri(clientId string, req *apireq.AddOauthClientRedirectUri) (*model.OauthClientRedirectUri, error)
+	DeleteRedirectUri(sysAccId int, clientId string, redirectUriId int) error
 }
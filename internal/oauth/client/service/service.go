@@ -1,7 +1,12 @@
 package service
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"oauth2-console-go/config"
 	"oauth2-console-go/dto/apireq"
 	"oauth2-console-go/dto/apires"
 	"oauth2-console-go/dto/model"
@@ -9,9 +14,14 @@ import (
 	"oauth2-console-go/internal/oauth/library"
 	"oauth2-console-go/internal/oauth/scope"
 	"oauth2-console-go/internal/system/sys_account"
+	sysAccLibrary "oauth2-console-go/internal/system/sys_account/library"
+	"oauth2-console-go/pkg/client_secret"
 	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/helper"
 	"oauth2-console-go/pkg/logr"
+	"oauth2-console-go/pkg/storage"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -20,51 +30,77 @@ type Service struct {
 	sysAccRepo  sys_account.Repository
 	clientRepo  client.Repository
 	clientCache client.Cache
+	storage     storage.Storage
 }
 
-func NewService(sar sys_account.Repository, ocr client.Repository, occ client.Cache) client.Service {
+// iconKeyPrefix client icon 以檔案內容 hash 命名，相同的圖片只保存一份
+const iconKeyPrefix = "oauth_client/icon"
+
+func NewService(sar sys_account.Repository, ocr client.Repository, occ client.Cache, st storage.Storage) client.Service {
 	return &Service{
 		sysAccRepo:  sar,
 		clientRepo:  ocr,
 		clientCache: occ,
+		storage:     st,
 	}
 }
 
-func (s *Service) ListClient(sysAccId int, page, perPage int) (*apires.ListOauthClient, error) {
-	// Check account id exist
-	sysAcc := model.SysAccount{Id: sysAccId}
-	acc, err := s.sysAccRepo.FindOne(&sysAcc)
+func (s *Service) ListClient(req *apireq.ListOauthClient) (*apires.ListOauthClient, error) {
+	acc, err := s.checkAccount(req.AccountId)
 	if err != nil {
-		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
-		return nil, findErr
+		return nil, err
 	}
-	if acc == nil || acc.IsDisable {
-		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", err)
-		return nil, notFoundErr
+
+	sort, err := helper.ParseSort(req.Sort, client.SortFields)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := client.ListFilter{
+		SysAccountId: req.OwnerId,
+		Keyword:      req.Keyword,
+		Domain:       req.Domain,
+		Scope:        req.Scope,
+		CreatedFrom:  req.CreatedFrom,
+		CreatedTo:    req.CreatedTo,
+		UpdatedFrom:  req.UpdatedFrom,
+		UpdatedTo:    req.UpdatedTo,
+		Sort:         sort,
 	}
 
-	total, err := s.clientRepo.Count()
+	// 非 admin 只列出自己的 client
+	if acc.Role != model.RoleAdmin {
+		filter.SysAccountId = acc.Id
+	}
+
+	total, err := s.clientRepo.Count(&filter)
 	if err != nil {
 		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "count client error.", err)
 		return nil, unknownErr
 	}
 
+	page := req.Page
 	if page <= 1 {
 		page = 1
 	}
 
+	perPage := req.PerPage
 	if perPage <= 1 {
 		perPage = 1
 	}
 
 	offset := (page - 1) * perPage
 
-	list, err := s.clientRepo.Find(perPage, offset)
+	list, err := s.clientRepo.Find(&filter, perPage, offset)
 	if err != nil {
 		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find client error.", err)
 		return nil, unknownErr
 	}
 
+	for _, item := range list {
+		item.IconUrl = s.iconUrl(item.IconPath)
+	}
+
 	res := apires.ListOauthClient{
 		List:        list,
 		Total:       total,
@@ -76,27 +112,15 @@ func (s *Service) ListClient(sysAccId int, page, perPage int) (*apires.ListOauth
 }
 
 func (s *Service) GetClient(sysAccId int, clientId string, scopeRepo scope.Repository) (*apires.OauthClient, error) {
-	// Check account id exist
-	sysAcc := model.SysAccount{Id: sysAccId}
-	acc, err := s.sysAccRepo.FindOne(&sysAcc)
+	acc, err := s.checkAccount(sysAccId)
 	if err != nil {
-		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
-		return nil, findErr
-	}
-	if acc == nil || acc.IsDisable {
-		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", err)
-		return nil, notFoundErr
+		return nil, err
 	}
 
-	// 取得 client app 資訊
-	clt, err := s.clientRepo.FindOne(&model.OauthClient{Id: clientId})
+	// 取得 client app 資訊，非 admin 只能存取自己的 client
+	clt, err := s.findOwnedClient(acc, clientId)
 	if err != nil {
-		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find client error.", err)
-		return nil, findErr
-	}
-	if clt == nil {
-		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "client not found.", err)
-		return nil, notFoundErr
+		return nil, err
 	}
 
 	// 取得所有 API 列表
@@ -118,90 +142,114 @@ func (s *Service) GetClient(sysAccId int, clientId string, scopeRepo scope.Repos
 		return nil, err
 	}
 
+	// 取得 redirect uri 列表
+	redirectUris, err := s.clientRepo.FindRedirectUri(clt.Id)
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find redirect uri error.", err)
+		return nil, findErr
+	}
+
 	res := apires.OauthClient{
-		Id:           clt.Id,
-		SysAccountId: clt.SysAccountId,
-		Name:         clt.Name,
-		Secret:       clt.Secret,
-		Domain:       clt.Domain,
-		Scope:        clt.Scope,
-		IconPath:     clt.IconPath,
-		ScopeList:    scopeList,
-		CreatedAt:    clt.CreatedAt,
-		UpdatedAt:    clt.UpdatedAt,
+		Id:            clt.Id,
+		SysAccountId:  clt.SysAccountId,
+		Name:          clt.Name,
+		Domain:        clt.Domain,
+		Scope:         clt.Scope,
+		IconPath:      clt.IconPath,
+		IconUrl:       s.iconUrl(clt.IconPath),
+		GrantTypes:    strings.Fields(clt.GrantTypes),
+		ResponseTypes: strings.Fields(clt.ResponseTypes),
+		PkceRequired:  clt.PkceRequired,
+		PkceS256Only:  clt.PkceS256Only,
+		ScopeList:     scopeList,
+		RedirectUris:  redirectUris,
+		CreatedAt:     clt.CreatedAt,
+		UpdatedAt:     clt.UpdatedAt,
 	}
 
 	return &res, nil
 }
 
-func (s *Service) AddClient(req *apireq.AddOauthClientWithFile) error {
+func (s *Service) AddClient(req *apireq.AddOauthClientWithFile) (*apires.OauthClientSecret, error) {
 	// Check account id exist
 	sysAcc := model.SysAccount{Id: req.AccountId}
 	acc, err := s.sysAccRepo.FindOne(&sysAcc)
 	if err != nil {
 		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
-		return findErr
+		return nil, findErr
 	}
 	if acc == nil || acc.IsDisable {
 		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", err)
-		return notFoundErr
+		return nil, notFoundErr
 	}
 
 	// Check client id unique
 	clt, err := s.clientRepo.FindOne(&model.OauthClient{Id: req.Id})
 	if err != nil {
 		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find client error.", err)
-		return findErr
+		return nil, findErr
 	}
 	if clt != nil {
 		duplicateErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "client id duplicate error.", err)
-		return duplicateErr
+		return nil, duplicateErr
+	}
+
+	// 由伺服器產生 secret，資料庫只保存 hash
+	secret, err := client_secret.Generate()
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "generate client secret error.", err)
+		return nil, unknownErr
 	}
 
 	// 上傳檔案
-	// TODO - Upload image file
+	iconPath := ""
+	if req.File != nil {
+		iconPath, err = s.uploadIcon(req.File, req.FileExtension)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Insert client
 	m := model.OauthClient{
-		Id:           req.Id,
-		SysAccountId: req.AccountId,
-		Name:         req.Name,
-		Secret:       req.Secret,
-		Domain:       req.Domain,
-		IconPath:     "",
+		Id:              req.Id,
+		SysAccountId:    req.AccountId,
+		Name:            req.Name,
+		Secret:          client_secret.Hash(secret),
+		SecretCreatedAt: time.Now().UTC(),
+		Domain:          req.Domain,
+		IconPath:        iconPath,
+		GrantTypes:      model.DefaultGrantTypes,
+		ResponseTypes:   model.DefaultResponseTypes,
 	}
 
 	err = s.clientRepo.Insert(&m)
 	if err != nil {
 		// 新增 Client App 失敗，刪除檔案
-		// TODO - Delete upload image file
+		s.deleteIcon(iconPath)
+
+		insertErr := er.NewAppErr(http.StatusInternalServerError, er.DBInsertError, "insert client error.", err)
+		return nil, insertErr
 	}
 
-	return nil
+	res := apires.OauthClientSecret{
+		ClientId: m.Id,
+		Secret:   secret,
+	}
+
+	return &res, nil
 }
 
 func (s *Service) EditClient(clientId string, req *apireq.EditOauthClientWithFile, scopeRepo scope.Repository) error {
-	// Check account id exist
-	sysAcc := model.SysAccount{Id: req.AccountId}
-	acc, err := s.sysAccRepo.FindOne(&sysAcc)
+	acc, err := s.checkAccount(req.AccountId)
 	if err != nil {
-		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
-		return findErr
-	}
-	if acc == nil || acc.IsDisable {
-		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", err)
-		return notFoundErr
+		return err
 	}
 
-	// Check client exist
-	clt, err := s.clientRepo.FindOne(&model.OauthClient{Id: clientId})
+	// Check client exist，非 admin 只能存取自己的 client
+	clt, err := s.findOwnedClient(acc, clientId)
 	if err != nil {
-		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find client error.", err)
-		return findErr
-	}
-	if clt == nil {
-		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "client not found.", err)
-		return notFoundErr
+		return err
 	}
 
 	// 從上傳的授權清單內找出授權項目
@@ -226,37 +274,76 @@ func (s *Service) EditClient(clientId string, req *apireq.EditOauthClientWithFil
 		return err
 	}
 
-	// 若是 has_image 為 true ，則檢查圖片
-	// var fileName string
-	// var uploadFilePath string
+	// 授權流程，未帶入的欄位沿用原設定
+	grants := strings.Fields(clt.GrantTypes)
+	responses := strings.Fields(clt.ResponseTypes)
+	if req.GrantTypes != nil {
+		grants = req.GrantTypes
+		responses = req.ResponseTypes
+	} else if req.ResponseTypes != nil {
+		responses = req.ResponseTypes
+	}
+	pkceRequired := clt.PkceRequired
+	if req.PkceRequired != nil {
+		pkceRequired = *req.PkceRequired
+	}
+	pkceS256Only := clt.PkceS256Only
+	if req.PkceS256Only != nil {
+		pkceS256Only = *req.PkceS256Only
+	}
+
+	err = library.ValidateGrantPolicy(grants, responses, pkceRequired, pkceS256Only)
+	if err != nil {
+		return err
+	}
 
-	if *req.HasImage {
-		// 上傳檔案
-		// TODO - Upload image file
+	// 若是 has_image 為 true ，則上傳新的圖片
+	iconPath := clt.IconPath
+	if *req.HasImage && req.File != nil {
+		iconPath, err = s.uploadIcon(req.File, req.FileExtension)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Update client
 	m := model.OauthClient{
 		Id:           clt.Id,
-		SysAccountId: req.AccountId,
+		SysAccountId: clt.SysAccountId,
 		Name:         req.Name,
-		Secret:       req.Secret,
+		Secret:       clt.Secret,
 		Domain:       req.Domain,
 		Scope:        strings.Join(validScopes, " "),
-		IconPath:     clt.IconPath,
+		IconPath:     iconPath,
+
+		GrantTypes:    strings.Join(grants, " "),
+		ResponseTypes: strings.Join(responses, " "),
+		PkceRequired:  pkceRequired,
+		PkceS256Only:  pkceS256Only,
+
+		// 保留 secret 輪替資訊
+		SecretCreatedAt:         clt.SecretCreatedAt,
+		PreviousSecret:          clt.PreviousSecret,
+		PreviousSecretCreatedAt: clt.PreviousSecretCreatedAt,
+		PreviousSecretExpiredAt: clt.PreviousSecretExpiredAt,
 	}
 
 	err = s.clientRepo.Update(&m)
 	if err != nil {
-		if *req.HasImage {
+		if iconPath != clt.IconPath {
 			// 編輯 Client App 失敗，刪除檔案
-			// TODO - Delete upload image file
+			s.deleteIcon(iconPath)
 		}
 
 		updateErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "update client error.", err)
 		return updateErr
 	}
 
+	// 刪除更換前的圖片
+	if iconPath != clt.IconPath {
+		s.deleteIcon(clt.IconPath)
+	}
+
 	// Delete cache
 	err = s.clientCache.DeleteClientScopeList(clientId)
 	if err != nil {
@@ -265,3 +352,405 @@ func (s *Service) EditClient(clientId string, req *apireq.EditOauthClientWithFil
 
 	return nil
 }
+
+func (s *Service) DeleteClient(sysAccId int, clientId string) error {
+	acc, err := s.checkAccount(sysAccId)
+	if err != nil {
+		return err
+	}
+
+	// Check client exist，非 admin 只能存取自己的 client
+	clt, err := s.findOwnedClient(acc, clientId)
+	if err != nil {
+		return err
+	}
+
+	err = s.clientRepo.Delete(clt.Id)
+	if err != nil {
+		deleteErr := er.NewAppErr(http.StatusInternalServerError, er.DBDeleteError, "delete client error.", err)
+		return deleteErr
+	}
+
+	// 刪除圖片
+	s.deleteIcon(clt.IconPath)
+
+	// Delete cache，授權伺服器不再使用此 client 的授權清單
+	err = s.clientCache.DeleteClientScopeList(clt.Id)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete oauth client scope list cache error.", err)
+		return redisErr
+	}
+
+	return nil
+}
+
+func (s *Service) TransferClient(clientId string, req *apireq.TransferOauthClient) error {
+	acc, err := s.checkAccount(req.AccountId)
+	if err != nil {
+		return err
+	}
+
+	clt, err := s.findOwnedClient(acc, clientId)
+	if err != nil {
+		return err
+	}
+
+	// 新的擁有者須可編輯 client
+	target, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: req.ToAccountId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+	if target == nil || target.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "target account not found.", nil)
+		return notFoundErr
+	}
+	if !sysAccLibrary.HasRole(target.Role, model.RoleEditor) {
+		roleErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "target account must be an editor or admin.", nil)
+		return roleErr
+	}
+	if clt.SysAccountId == target.Id {
+		return nil
+	}
+
+	clt.SysAccountId = target.Id
+	err = s.clientRepo.Update(clt)
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update client error.", err)
+		return updateErr
+	}
+
+	return nil
+}
+
+func (s *Service) RotateSecret(clientId string, req *apireq.RotateOauthClientSecret) (*apires.OauthClientSecret, error) {
+	acc, err := s.checkAccount(req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	clt, err := s.findOwnedClient(acc, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := client_secret.Generate()
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "generate client secret error.", err)
+		return nil, unknownErr
+	}
+
+	grace := config.ClientSecretGraceTime
+	if req.GraceHours != nil {
+		grace = time.Hour * time.Duration(*req.GraceHours)
+	}
+
+	// 舊的 secret 於寬限期內仍可使用，寬限期為 0 時立即失效
+	// 寬限期內再次輪替，只保留最近一次輪替前的 secret
+	now := time.Now().UTC()
+	clt.PreviousSecret = ""
+	clt.PreviousSecretCreatedAt = time.Time{}
+	clt.PreviousSecretExpiredAt = time.Time{}
+	if grace > 0 {
+		clt.PreviousSecret = clt.Secret
+		clt.PreviousSecretCreatedAt = clt.SecretCreatedAt
+		if clt.PreviousSecretCreatedAt.IsZero() {
+			clt.PreviousSecretCreatedAt = clt.CreatedAt
+		}
+		clt.PreviousSecretExpiredAt = now.Add(grace)
+	}
+	clt.Secret = client_secret.Hash(secret)
+	clt.SecretCreatedAt = now
+
+	err = s.clientRepo.Update(clt)
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update client error.", err)
+		return nil, updateErr
+	}
+
+	res := apires.OauthClientSecret{
+		ClientId: clt.Id,
+		Secret:   secret,
+	}
+	if clt.PreviousSecret != "" {
+		res.PreviousSecretExpiredAt = &clt.PreviousSecretExpiredAt
+	}
+
+	return &res, nil
+}
+
+func (s *Service) ListSecret(sysAccId int, clientId string) (*apires.ListOauthClientSecret, error) {
+	acc, err := s.checkAccount(sysAccId)
+	if err != nil {
+		return nil, err
+	}
+
+	clt, err := s.findOwnedClient(acc, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	current := apires.OauthClientSecretItem{Type: "current"}
+	createdAt := clt.SecretCreatedAt
+	if createdAt.IsZero() {
+		createdAt = clt.CreatedAt
+	}
+	current.CreatedAt = &createdAt
+
+	list := []*apires.OauthClientSecretItem{&current}
+
+	// 已過期的舊 secret 不列出
+	if clt.PreviousSecret != "" && clt.PreviousSecretExpiredAt.After(time.Now().UTC()) {
+		previous := apires.OauthClientSecretItem{
+			Type:      "previous",
+			CreatedAt: &clt.PreviousSecretCreatedAt,
+			ExpiredAt: &clt.PreviousSecretExpiredAt,
+		}
+		list = append(list, &previous)
+	}
+
+	res := apires.ListOauthClientSecret{
+		List: list,
+	}
+
+	return &res, nil
+}
+
+func (s *Service) RevokePreviousSecret(sysAccId int, clientId string) error {
+	acc, err := s.checkAccount(sysAccId)
+	if err != nil {
+		return err
+	}
+
+	clt, err := s.findOwnedClient(acc, clientId)
+	if err != nil {
+		return err
+	}
+	if clt.PreviousSecret == "" {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "previous secret not found.", nil)
+		return notFoundErr
+	}
+
+	clt.PreviousSecret = ""
+	clt.PreviousSecretCreatedAt = time.Time{}
+	clt.PreviousSecretExpiredAt = time.Time{}
+	err = s.clientRepo.Update(clt)
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update client error.", err)
+		return updateErr
+	}
+
+	return nil
+}
+
+func (s *Service) ListRedirectUri(sysAccId int, clientId string) (*apires.ListOauthClientRedirectUri, error) {
+	acc, err := s.checkAccount(sysAccId)
+	if err != nil {
+		return nil, err
+	}
+
+	clt, err := s.findOwnedClient(acc, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.clientRepo.FindRedirectUri(clt.Id)
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find redirect uri error.", err)
+		return nil, findErr
+	}
+
+	res := apires.ListOauthClientRedirectUri{
+		List: list,
+	}
+
+	return &res, nil
+}
+
+func (s *Service) AddRedirectUri(clientId string, req *apireq.AddOauthClientRedirectUri) (*model.OauthClientRedirectUri, error) {
+	acc, err := s.checkAccount(req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	clt, err := s.findOwnedClient(acc, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = library.ValidateRedirectUri(req.RedirectUri)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.clientRepo.FindRedirectUri(clt.Id)
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find redirect uri error.", err)
+		return nil, findErr
+	}
+	if len(list) >= config.RedirectUriMaxCount {
+		limitErr := er.NewAppErr(http.StatusBadRequest, er.LimitExceededError, "redirect uri count limit exceeded.", nil)
+		return nil, limitErr
+	}
+
+	// 授權伺服器以完全相符比對，相同字串才視為重複
+	for _, item := range list {
+		if item.RedirectUri == req.RedirectUri {
+			duplicateErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "redirect uri duplicate error.", nil)
+			return nil, duplicateErr
+		}
+	}
+
+	m := model.OauthClientRedirectUri{
+		OauthClientId: clt.Id,
+		RedirectUri:   req.RedirectUri,
+	}
+	err = s.clientRepo.InsertRedirectUri(&m)
+	if err != nil {
+		insertErr := er.NewAppErr(http.StatusInternalServerError, er.DBInsertError, "insert redirect uri error.", err)
+		return nil, insertErr
+	}
+
+	return &m, nil
+}
+
+func (s *Service) DeleteRedirectUri(sysAccId int, clientId string, redirectUriId int) error {
+	acc, err := s.checkAccount(sysAccId)
+	if err != nil {
+		return err
+	}
+
+	clt, err := s.findOwnedClient(acc, clientId)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.clientRepo.DeleteRedirectUri(clt.Id, redirectUriId)
+	if err != nil {
+		deleteErr := er.NewAppErr(http.StatusInternalServerError, er.DBDeleteError, "delete redirect uri error.", err)
+		return deleteErr
+	}
+	if !deleted {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "redirect uri not found.", nil)
+		return notFoundErr
+	}
+
+	return nil
+}
+
+// checkAccount 確認操作的帳號存在且未停用
+func (s *Service) checkAccount(sysAccId int) (*model.SysAccount, error) {
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: sysAccId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc == nil || acc.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return nil, notFoundErr
+	}
+
+	return acc, nil
+}
+
+// findOwnedClient 取得帳號擁有的 client，admin 可存取所有 client
+// 不屬於帳號的 client 視同不存在，避免洩漏 client id
+func (s *Service) findOwnedClient(acc *model.SysAccount, clientId string) (*model.OauthClient, error) {
+	clt, err := s.findClient(clientId)
+	if err != nil {
+		return nil, err
+	}
+	if acc.Role != model.RoleAdmin && clt.SysAccountId != acc.Id {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "client not found.", nil)
+		return nil, notFoundErr
+	}
+
+	return clt, nil
+}
+
+func (s *Service) findClient(clientId string) (*model.OauthClient, error) {
+	clt, err := s.clientRepo.FindOne(&model.OauthClient{Id: clientId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find client error.", err)
+		return nil, findErr
+	}
+	if clt == nil {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "client not found.", nil)
+		return nil, notFoundErr
+	}
+
+	return clt, nil
+}
+
+// uploadIcon 上傳 client icon，回傳儲存的 key
+func (s *Service) uploadIcon(file io.Reader, ext string) (string, error) {
+	if s.storage == nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "storage is not configured.", nil)
+		return "", unknownErr
+	}
+
+	limit := int64(config.ClientIconSizeLimit * helper.MB)
+	data, err := ioutil.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		readErr := er.NewAppErr(http.StatusBadRequest, er.UploadFileErrUnknown, "read upload file error.", err)
+		return "", readErr
+	}
+	if len(data) == 0 {
+		emptyErr := er.NewAppErr(http.StatusBadRequest, er.UploadFileErrEmpty, "the file is empty or isn't exist.", nil)
+		return "", emptyErr
+	}
+	if int64(len(data)) > limit {
+		sizeErr := er.NewAppErr(http.StatusBadRequest, er.UploadFileErrSizeOverLimit, fmt.Sprintf("max file size is %dMB.", config.ClientIconSizeLimit), nil)
+		return "", sizeErr
+	}
+
+	contentType := "image/png"
+	if ext == helper.ImgTypeJpeg {
+		contentType = "image/jpeg"
+	}
+
+	key := storage.ContentKey(iconKeyPrefix, data, ext)
+	err = s.storage.Put(key, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		uploadErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "upload icon error.", err)
+		return "", uploadErr
+	}
+
+	return key, nil
+}
+
+// deleteIcon 刪除不再被任何 client 使用的 icon，失敗時只記錄 log
+func (s *Service) deleteIcon(key string) {
+	if key == "" || s.storage == nil {
+		return
+	}
+
+	// 相同內容的 icon 共用同一個 key
+	other, err := s.clientRepo.FindOne(&model.OauthClient{IconPath: key})
+	if err != nil {
+		logr.L.Error("find oauth client by icon error.", zap.String("icon_path", key), zap.String("error", err.Error()))
+		return
+	}
+	if other != nil {
+		return
+	}
+
+	err = s.storage.Delete(key)
+	if err != nil {
+		logr.L.Error("delete oauth client icon error.", zap.String("icon_path", key), zap.String("error", err.Error()))
+	}
+}
+
+func (s *Service) iconUrl(key string) string {
+	if key == "" || s.storage == nil {
+		return ""
+	}
+
+	u, err := s.storage.Url(key)
+	if err != nil {
+		logr.L.Error("get oauth client icon url error.", zap.String("icon_path", key), zap.String("error", err.Error()))
+		return ""
+	}
+
+	return u
+}
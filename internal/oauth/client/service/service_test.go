@@ -2,6 +2,8 @@ package service
 
 import (
 	"encoding/json"
+	"io"
+	"io/ioutil"
 	"oauth2-console-go/config"
 	"oauth2-console-go/driver"
 	"oauth2-console-go/dto/apireq"
@@ -9,23 +11,43 @@ import (
 	clientRepo "oauth2-console-go/internal/oauth/client/repository"
 	scopeRepo "oauth2-console-go/internal/oauth/scope/repository"
 	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	"oauth2-console-go/pkg/client_secret"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/storage"
 	"oauth2-console-go/pkg/valider"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
+var iconDir string
+
 func TestMain(m *testing.M) {
 	setUp()
 	code := m.Run()
+	_ = os.RemoveAll(iconDir)
 	os.Exit(code)
 }
 
 func setUp() {
 	config.InitEnv()
 	valider.Init()
+	iconDir, _ = ioutil.TempDir("", "oauth_client_icon")
+}
+
+// iconFile 產生測試用的上傳檔案
+func iconFile(t *testing.T, content string) *os.File {
+	f, err := ioutil.TempFile(iconDir, "upload-*.png")
+	assert.Nil(t, err)
+	_, _ = f.WriteString(content)
+	_, _ = f.Seek(0, io.SeekStart)
+	return f
 }
 
 func TestService_ListClient(t *testing.T) {
@@ -36,15 +58,19 @@ func TestService_ListClient(t *testing.T) {
 	sar := sysAccRepo.NewRepository(orm)
 	occ := clientRepo.NewCache(re)
 	ocr := clientRepo.NewRepository(orm)
-	ocs := NewService(sar, ocr, occ)
+	ocs := NewService(sar, ocr, occ, storage.NewLocal(iconDir, "/storage"))
 
 	// Act
-	res, err := ocs.ListClient(1, 1, 10)
+	res, err := ocs.ListClient(&apireq.ListOauthClient{AccountId: 1, Page: 1, PerPage: 10})
 
 	// Assert
 	assert.Nil(t, err)
 	assert.Equal(t, 2, res.Total)
 	assert.Len(t, res.List, 2)
+
+	// 排序欄位不在清單內
+	_, err = ocs.ListClient(&apireq.ListOauthClient{AccountId: 1, Page: 1, PerPage: 10, Sort: "secret"})
+	assert.NotNil(t, err)
 }
 
 func TestService_GetClient(t *testing.T) {
@@ -56,7 +82,7 @@ func TestService_GetClient(t *testing.T) {
 	osr := scopeRepo.NewRepository(orm)
 	occ := clientRepo.NewCache(re)
 	ocr := clientRepo.NewRepository(orm)
-	ocs := NewService(sar, ocr, occ)
+	ocs := NewService(sar, ocr, occ, storage.NewLocal(iconDir, "/storage"))
 
 	// Act
 	res, err := ocs.GetClient(1, "address-book-go", osr)
@@ -75,14 +101,13 @@ func TestService_EditClient(t *testing.T) {
 	osr := scopeRepo.NewRepository(orm)
 	occ := clientRepo.NewCache(re)
 	ocr := clientRepo.NewRepository(orm)
-	ocs := NewService(sar, ocr, occ)
+	ocs := NewService(sar, ocr, occ, storage.NewLocal(iconDir, "/storage"))
 
 	clientId := "address-book-go"
 	hasImage := false
 
 	req := apireq.EditOauthClient{
 		AccountId: 1,
-		Secret:    "12345678",
 		Domain:    "http://abc.test.com/",
 		Name:      "Test update client name",
 		HasImage:  &hasImage,
@@ -115,3 +140,432 @@ func TestService_EditClient(t *testing.T) {
 	// Teardown
 	_, _ = orm.ID(client.Id).Update(&client)
 }
+
+func TestService_DeleteClient(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	re, _ := driver.NewRedis()
+
+	sar := sysAccRepo.NewRepository(orm)
+	occ := clientRepo.NewCache(re)
+	ocr := clientRepo.NewRepository(orm)
+	st := storage.NewLocal(iconDir, "/storage")
+	ocs := NewService(sar, ocr, occ, st)
+
+	iconKey := storage.ContentKey("oauth_client/icon", []byte("delete icon"), ".png")
+	_ = st.Put(iconKey, strings.NewReader("delete icon"), int64(len("delete icon")), "image/png")
+
+	client := model.OauthClient{
+		Id:           "test_delete_client",
+		SysAccountId: 1,
+		Name:         "Test Delete Client",
+		Secret:       "pa@@w0rd",
+		Domain:       "http://localhost:9088",
+		IconPath:     iconKey,
+	}
+	_ = ocr.Insert(&client)
+
+	// 相同內容的圖片共用同一個檔案
+	shared := model.OauthClient{
+		Id:           "test_delete_client_shared",
+		SysAccountId: 1,
+		Name:         "Test Delete Client Shared",
+		Secret:       "pa@@w0rd",
+		Domain:       "http://localhost:9089",
+		IconPath:     iconKey,
+	}
+	_ = ocr.Insert(&shared)
+
+	// Act
+	err := ocs.DeleteClient(1, client.Id)
+
+	// Assert
+	assert.Nil(t, err)
+	clt, _ := ocr.FindOne(&model.OauthClient{Id: client.Id})
+	assert.Nil(t, clt)
+
+	// 其他 client 仍在使用，不刪除圖片
+	_, err = os.Stat(filepath.Join(iconDir, iconKey))
+	assert.Nil(t, err)
+
+	// 最後一個使用的 client 刪除時一併刪除圖片
+	err = ocs.DeleteClient(1, shared.Id)
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(iconDir, iconKey))
+	assert.True(t, os.IsNotExist(err))
+
+	// Not found
+	err = ocs.DeleteClient(1, client.Id)
+	assert.NotNil(t, err)
+}
+
+func TestService_AddClient(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	re, _ := driver.NewRedis()
+
+	sar := sysAccRepo.NewRepository(orm)
+	occ := clientRepo.NewCache(re)
+	ocr := clientRepo.NewRepository(orm)
+	ocs := NewService(sar, ocr, occ, storage.NewLocal(iconDir, "/storage"))
+
+	req := apireq.AddOauthClientWithFile{
+		AddOauthClient: &apireq.AddOauthClient{
+			AccountId: 1,
+			Id:        "test_add_client",
+			Domain:    "http://localhost:9088",
+			Name:      "Test Add Client",
+		},
+	}
+
+	// Act
+	res, err := ocs.AddClient(&req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, req.Id, res.ClientId)
+	assert.NotEmpty(t, res.Secret)
+
+	// 只保存 hash
+	clt, _ := ocr.FindOne(&model.OauthClient{Id: req.Id})
+	assert.NotEqual(t, res.Secret, clt.Secret)
+	assert.True(t, client_secret.Verify(clt.Secret, res.Secret))
+
+	// Rotate，寬限期為 0 時舊的 secret 立即失效
+	graceHours := 0
+	rotateRes, err := ocs.RotateSecret(req.Id, &apireq.RotateOauthClientSecret{AccountId: 1, GraceHours: &graceHours})
+	assert.Nil(t, err)
+	assert.NotEqual(t, res.Secret, rotateRes.Secret)
+	assert.Nil(t, rotateRes.PreviousSecretExpiredAt)
+	clt, _ = ocr.FindOne(&model.OauthClient{Id: req.Id})
+	assert.False(t, client_secret.VerifyClient(clt, res.Secret, time.Now()))
+	assert.True(t, client_secret.VerifyClient(clt, rotateRes.Secret, time.Now()))
+
+	// Teardown
+	_ = ocr.Delete(req.Id)
+}
+
+func TestService_RotateSecret(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	re, _ := driver.NewRedis()
+
+	sar := sysAccRepo.NewRepository(orm)
+	occ := clientRepo.NewCache(re)
+	ocr := clientRepo.NewRepository(orm)
+	ocs := NewService(sar, ocr, occ, storage.NewLocal(iconDir, "/storage"))
+
+	addRes, _ := ocs.AddClient(&apireq.AddOauthClientWithFile{
+		AddOauthClient: &apireq.AddOauthClient{
+			AccountId: 1,
+			Id:        "test_rotate_client",
+			Domain:    "http://localhost:9088",
+			Name:      "Test Rotate Client",
+		},
+	})
+
+	// Act
+	res, err := ocs.RotateSecret(addRes.ClientId, &apireq.RotateOauthClientSecret{AccountId: 1})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotNil(t, res.PreviousSecretExpiredAt)
+
+	// 寬限期內新舊 secret 皆可使用
+	clt, _ := ocr.FindOne(&model.OauthClient{Id: addRes.ClientId})
+	assert.True(t, client_secret.VerifyClient(clt, addRes.Secret, time.Now()))
+	assert.True(t, client_secret.VerifyClient(clt, res.Secret, time.Now()))
+
+	list, err := ocs.ListSecret(1, addRes.ClientId)
+	assert.Nil(t, err)
+	assert.Len(t, list.List, 2)
+
+	// 提前撤銷舊的 secret
+	err = ocs.RevokePreviousSecret(1, addRes.ClientId)
+	assert.Nil(t, err)
+	clt, _ = ocr.FindOne(&model.OauthClient{Id: addRes.ClientId})
+	assert.False(t, client_secret.VerifyClient(clt, addRes.Secret, time.Now()))
+	assert.True(t, client_secret.VerifyClient(clt, res.Secret, time.Now()))
+
+	list, _ = ocs.ListSecret(1, addRes.ClientId)
+	assert.Len(t, list.List, 1)
+
+	err = ocs.RevokePreviousSecret(1, addRes.ClientId)
+	assert.NotNil(t, err)
+
+	// Teardown
+	_ = ocr.Delete(addRes.ClientId)
+}
+
+func TestService_ClientIcon(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	re, _ := driver.NewRedis()
+
+	sar := sysAccRepo.NewRepository(orm)
+	osr := scopeRepo.NewRepository(orm)
+	occ := clientRepo.NewCache(re)
+	ocr := clientRepo.NewRepository(orm)
+	ocs := NewService(sar, ocr, occ, storage.NewLocal(iconDir, "/storage"))
+
+	file := iconFile(t, "first icon")
+	defer file.Close()
+
+	// Act
+	addRes, err := ocs.AddClient(&apireq.AddOauthClientWithFile{
+		AddOauthClient: &apireq.AddOauthClient{
+			AccountId: 1,
+			Id:        "test_icon_client",
+			Domain:    "http://localhost:9088",
+			Name:      "Test Icon Client",
+		},
+		File:          file,
+		FileName:      "icon.png",
+		FileExtension: ".png",
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	firstKey := storage.ContentKey("oauth_client/icon", []byte("first icon"), ".png")
+	_, err = os.Stat(filepath.Join(iconDir, firstKey))
+	assert.Nil(t, err)
+
+	res, err := ocs.GetClient(1, addRes.ClientId, osr)
+	assert.Nil(t, err)
+	assert.Equal(t, firstKey, res.IconPath)
+	assert.Equal(t, "/storage/"+firstKey, res.IconUrl)
+
+	// 更換圖片，刪除舊的圖片
+	newFile := iconFile(t, "second icon")
+	defer newFile.Close()
+	hasImage := true
+	scopeList := model.ScopeList{}
+	err = ocs.EditClient(addRes.ClientId, &apireq.EditOauthClientWithFile{
+		EditOauthClient: &apireq.EditOauthClient{
+			AccountId: 1,
+			Domain:    "http://localhost:9088",
+			Name:      "Test Icon Client",
+			HasImage:  &hasImage,
+		},
+		File:          newFile,
+		FileName:      "icon.png",
+		FileExtension: ".png",
+		ScopeList:     &scopeList,
+	}, osr)
+	assert.Nil(t, err)
+	secondKey := storage.ContentKey("oauth_client/icon", []byte("second icon"), ".png")
+	_, err = os.Stat(filepath.Join(iconDir, secondKey))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(iconDir, firstKey))
+	assert.True(t, os.IsNotExist(err))
+
+	// 刪除 client 時刪除圖片
+	err = ocs.DeleteClient(1, addRes.ClientId)
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(iconDir, secondKey))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestService_ClientIconWithoutStorage(t *testing.T) {
+	// Arrange
+	s := Service{}
+
+	// Act
+	_, err := s.uploadIcon(strings.NewReader("icon"), ".png")
+
+	// Assert
+	assert.NotNil(t, err)
+	assert.Equal(t, "", s.iconUrl("oauth_client/icon/test.png"))
+	s.deleteIcon("oauth_client/icon/test.png")
+}
+
+func TestService_RedirectUri(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	re, _ := driver.NewRedis()
+
+	sar := sysAccRepo.NewRepository(orm)
+	occ := clientRepo.NewCache(re)
+	ocr := clientRepo.NewRepository(orm)
+	ocs := NewService(sar, ocr, occ, storage.NewLocal(iconDir, "/storage"))
+
+	addRes, _ := ocs.AddClient(&apireq.AddOauthClientWithFile{
+		AddOauthClient: &apireq.AddOauthClient{
+			AccountId: 1,
+			Id:        "test_redirect_uri_client",
+			Domain:    "https://localhost:9088",
+			Name:      "Test Redirect Uri Client",
+		},
+	})
+
+	// Act
+	m, err := ocs.AddRedirectUri(addRes.ClientId, &apireq.AddOauthClientRedirectUri{
+		AccountId:   1,
+		RedirectUri: "com.example.app:/oauth2redirect",
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotZero(t, m.Id)
+
+	// 重複及格式錯誤
+	_, err = ocs.AddRedirectUri(addRes.ClientId, &apireq.AddOauthClientRedirectUri{
+		AccountId:   1,
+		RedirectUri: "com.example.app:/oauth2redirect",
+	})
+	assert.NotNil(t, err)
+	_, err = ocs.AddRedirectUri(addRes.ClientId, &apireq.AddOauthClientRedirectUri{
+		AccountId:   1,
+		RedirectUri: "http://example.com/callback",
+	})
+	assert.NotNil(t, err)
+
+	list, err := ocs.ListRedirectUri(1, addRes.ClientId)
+	assert.Nil(t, err)
+	assert.Len(t, list.List, 1)
+
+	err = ocs.DeleteRedirectUri(1, addRes.ClientId, m.Id)
+	assert.Nil(t, err)
+	err = ocs.DeleteRedirectUri(1, addRes.ClientId, m.Id)
+	assert.NotNil(t, err)
+
+	// Teardown
+	_ = ocr.Delete(addRes.ClientId)
+}
+
+func TestService_EditClientGrantPolicy(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	re, _ := driver.NewRedis()
+
+	sar := sysAccRepo.NewRepository(orm)
+	osr := scopeRepo.NewRepository(orm)
+	occ := clientRepo.NewCache(re)
+	ocr := clientRepo.NewRepository(orm)
+	ocs := NewService(sar, ocr, occ, storage.NewLocal(iconDir, "/storage"))
+
+	addRes, _ := ocs.AddClient(&apireq.AddOauthClientWithFile{
+		AddOauthClient: &apireq.AddOauthClient{
+			AccountId: 1,
+			Id:        "test_grant_client",
+			Domain:    "https://localhost:9088",
+			Name:      "Test Grant Client",
+		},
+	})
+
+	hasImage := false
+	pkce := true
+	scopeList := model.ScopeList{}
+	req := apireq.EditOauthClient{
+		AccountId:    1,
+		Domain:       "https://localhost:9088",
+		Name:         "Test Grant Client",
+		HasImage:     &hasImage,
+		PkceRequired: &pkce,
+		PkceS256Only: &pkce,
+	}
+	request := apireq.EditOauthClientWithFile{
+		EditOauthClient: &req,
+		ScopeList:       &scopeList,
+	}
+
+	// Act，沿用預設的 authorization code 流程
+	err := ocs.EditClient(addRes.ClientId, &request, osr)
+
+	// Assert
+	assert.Nil(t, err)
+	res, _ := ocs.GetClient(1, addRes.ClientId, osr)
+	assert.Equal(t, []string{model.GrantTypeAuthorizationCode, model.GrantTypeRefreshToken}, res.GrantTypes)
+	assert.Equal(t, []string{model.ResponseTypeCode}, res.ResponseTypes)
+	assert.True(t, res.PkceRequired)
+	assert.True(t, res.PkceS256Only)
+
+	// client credentials 不可設定 PKCE
+	req.GrantTypes = []string{model.GrantTypeClientCredentials}
+	err = ocs.EditClient(addRes.ClientId, &request, osr)
+	assert.NotNil(t, err)
+
+	pkce = false
+	err = ocs.EditClient(addRes.ClientId, &request, osr)
+	assert.Nil(t, err)
+	res, _ = ocs.GetClient(1, addRes.ClientId, osr)
+	assert.Equal(t, []string{model.GrantTypeClientCredentials}, res.GrantTypes)
+	assert.Len(t, res.ResponseTypes, 0)
+
+	// Teardown
+	_ = ocr.Delete(addRes.ClientId)
+}
+
+func TestService_ClientOwnership(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	re, _ := driver.NewRedis()
+
+	sar := sysAccRepo.NewRepository(orm)
+	osr := scopeRepo.NewRepository(orm)
+	occ := clientRepo.NewCache(re)
+	ocr := clientRepo.NewRepository(orm)
+	ocs := NewService(sar, ocr, occ, storage.NewLocal(iconDir, "/storage"))
+
+	editor := model.SysAccount{Account: "test_client_editor", Email: "test_client_editor@example.com", Name: "editor", Role: model.RoleEditor}
+	viewer := model.SysAccount{Account: "test_client_viewer", Email: "test_client_viewer@example.com", Name: "viewer", Role: model.RoleViewer}
+	_ = sar.Insert(&editor)
+	_ = sar.Insert(&viewer)
+
+	addRes, _ := ocs.AddClient(&apireq.AddOauthClientWithFile{
+		AddOauthClient: &apireq.AddOauthClient{
+			AccountId: editor.Id,
+			Id:        "test_owned_client",
+			Domain:    "https://localhost:9088",
+			Name:      "Test Owned Client",
+		},
+	})
+
+	// Act
+	list, err := ocs.ListClient(&apireq.ListOauthClient{AccountId: editor.Id, Page: 1, PerPage: 10, OwnerId: 1})
+
+	// Assert，只列出自己的 client，owner_id 只對 admin 有效
+	assert.Nil(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, addRes.ClientId, list.List[0].Id)
+
+	// admin 可看到所有 client，並可依擁有者查詢
+	list, _ = ocs.ListClient(&apireq.ListOauthClient{AccountId: 1, Page: 1, PerPage: 10})
+	assert.Equal(t, 3, list.Total)
+	list, _ = ocs.ListClient(&apireq.ListOauthClient{AccountId: 1, Page: 1, PerPage: 10, OwnerId: editor.Id})
+	assert.Equal(t, 1, list.Total)
+	_, err = ocs.GetClient(1, addRes.ClientId, osr)
+	assert.Nil(t, err)
+
+	// 無法存取其他帳號的 client
+	_, err = ocs.GetClient(editor.Id, "address-book-go", osr)
+	assert.NotNil(t, err)
+	err = ocs.DeleteClient(editor.Id, "address-book-go")
+	assert.NotNil(t, err)
+
+	// viewer 同樣只能存取自己的 client，其他帳號的 client 視同不存在
+	list, _ = ocs.ListClient(&apireq.ListOauthClient{AccountId: viewer.Id, Page: 1, PerPage: 10})
+	assert.Equal(t, 0, list.Total)
+	_, err = ocs.GetClient(viewer.Id, addRes.ClientId, osr)
+	notFoundErr := err.(*er.AppError)
+	assert.Equal(t, strconv.Itoa(er.ResourceNotFoundError), notFoundErr.Code)
+	_, err = ocs.ListSecret(viewer.Id, addRes.ClientId)
+	assert.NotNil(t, err)
+	_, err = ocs.ListRedirectUri(viewer.Id, addRes.ClientId)
+	assert.NotNil(t, err)
+
+	// 只能轉移給 editor 以上的帳號
+	err = ocs.TransferClient(addRes.ClientId, &apireq.TransferOauthClient{AccountId: editor.Id, ToAccountId: viewer.Id})
+	assert.NotNil(t, err)
+	err = ocs.TransferClient(addRes.ClientId, &apireq.TransferOauthClient{AccountId: editor.Id, ToAccountId: 1})
+	assert.Nil(t, err)
+	_, err = ocs.GetClient(editor.Id, addRes.ClientId, osr)
+	assert.NotNil(t, err)
+	clt, _ := ocr.FindOne(&model.OauthClient{Id: addRes.ClientId})
+	assert.Equal(t, 1, clt.SysAccountId)
+
+	// Teardown
+	_ = ocr.Delete(addRes.ClientId)
+	_, _ = orm.ID(editor.Id).Delete(&model.SysAccount{})
+	_, _ = orm.ID(viewer.Id).Delete(&model.SysAccount{})
+}
@@ -0,0 +1,73 @@
+package library
+
+import (
+	"net/http"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/er"
+)
+
+var (
+	grantTypes = map[string]bool{
+		model.GrantTypeAuthorizationCode: true,
+		model.GrantTypeClientCredentials: true,
+		model.GrantTypeRefreshToken:      true,
+		model.GrantTypeDeviceCode:        true,
+	}
+	responseTypes = map[string]bool{
+		model.ResponseTypeCode:  true,
+		model.ResponseTypeToken: true,
+	}
+)
+
+// ValidateGrantPolicy 檢查 client 可使用的授權流程組合
+// - response type code 與 authorization_code 必須同時設定
+// - response type token(implicit) 無法使用 PKCE
+// - refresh_token 須搭配 authorization_code 或 device_code，client_credentials 不發 refresh token
+// - PKCE 設定只適用於 authorization_code
+func ValidateGrantPolicy(grants, responses []string, pkceRequired, pkceS256Only bool) error {
+	if len(grants) == 0 {
+		return grantPolicyErr("grant types is required.")
+	}
+
+	grantSet, err := toSet(grants, grantTypes, "grant type")
+	if err != nil {
+		return err
+	}
+	responseSet, err := toSet(responses, responseTypes, "response type")
+	if err != nil {
+		return err
+	}
+
+	if grantSet[model.GrantTypeAuthorizationCode] != responseSet[model.ResponseTypeCode] {
+		return grantPolicyErr("authorization_code grant and code response type must be used together.")
+	}
+	if responseSet[model.ResponseTypeToken] && pkceRequired {
+		return grantPolicyErr("token response type can not be used with pkce required.")
+	}
+	if grantSet[model.GrantTypeRefreshToken] && !grantSet[model.GrantTypeAuthorizationCode] && !grantSet[model.GrantTypeDeviceCode] {
+		return grantPolicyErr("refresh_token grant requires authorization_code or device_code grant.")
+	}
+	if (pkceRequired || pkceS256Only) && !grantSet[model.GrantTypeAuthorizationCode] {
+		return grantPolicyErr("pkce requires authorization_code grant.")
+	}
+
+	return nil
+}
+
+func toSet(values []string, allowed map[string]bool, name string) (map[string]bool, error) {
+	set := map[string]bool{}
+	for _, v := range values {
+		if !allowed[v] {
+			return nil, grantPolicyErr(name + " " + v + " is not supported.")
+		}
+		if set[v] {
+			return nil, grantPolicyErr(name + " " + v + " is duplicated.")
+		}
+		set[v] = true
+	}
+	return set, nil
+}
+
+func grantPolicyErr(msg string) error {
+	return er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, msg, nil)
+}
@@ -0,0 +1,45 @@
+package library
+
+import (
+	"oauth2-console-go/dto/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGrantPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		grants       []string
+		responses    []string
+		pkceRequired bool
+		pkceS256Only bool
+		valid        bool
+	}{
+		{"authorization code", []string{model.GrantTypeAuthorizationCode, model.GrantTypeRefreshToken}, []string{model.ResponseTypeCode}, true, true, true},
+		{"client credentials", []string{model.GrantTypeClientCredentials}, nil, false, false, true},
+		{"device code", []string{model.GrantTypeDeviceCode, model.GrantTypeRefreshToken}, nil, false, false, true},
+		{"implicit", []string{model.GrantTypeAuthorizationCode}, []string{model.ResponseTypeCode, model.ResponseTypeToken}, false, false, true},
+		{"empty grant", nil, nil, false, false, false},
+		{"unknown grant", []string{"password"}, nil, false, false, false},
+		{"duplicate grant", []string{model.GrantTypeClientCredentials, model.GrantTypeClientCredentials}, nil, false, false, false},
+		{"unknown response", []string{model.GrantTypeAuthorizationCode}, []string{model.ResponseTypeCode, "id_token"}, false, false, false},
+		{"code without response", []string{model.GrantTypeAuthorizationCode}, nil, false, false, false},
+		{"response without code", []string{model.GrantTypeClientCredentials}, []string{model.ResponseTypeCode}, false, false, false},
+		{"implicit with pkce", []string{model.GrantTypeAuthorizationCode}, []string{model.ResponseTypeCode, model.ResponseTypeToken}, true, false, false},
+		{"refresh token only", []string{model.GrantTypeClientCredentials, model.GrantTypeRefreshToken}, nil, false, false, false},
+		{"pkce without code", []string{model.GrantTypeClientCredentials}, nil, false, true, false},
+	}
+
+	for _, tt := range tests {
+		// Act
+		err := ValidateGrantPolicy(tt.grants, tt.responses, tt.pkceRequired, tt.pkceS256Only)
+
+		// Assert
+		if tt.valid {
+			assert.Nil(t, err, tt.name)
+		} else {
+			assert.NotNil(t, err, tt.name)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package library
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"oauth2-console-go/pkg/er"
+	"strings"
+)
+
+const RedirectUriMaxLength = 512
+
+// ValidateRedirectUri 檢查 redirect uri，授權伺服器以完全相符比對
+// - 必須為絕對路徑，不可帶有 fragment、帳號密碼及萬用字元
+// - http 只允許 loopback(localhost, 127.0.0.1, [::1])，其餘須使用 https
+// - 原生 app 使用反向網域的自訂 scheme，e.g. com.example.app:/oauth2redirect (RFC 8252)
+func ValidateRedirectUri(redirectUri string) error {
+	if redirectUri == "" || len(redirectUri) > RedirectUriMaxLength {
+		return redirectUriErr("redirect uri length must be between 1 and 512.")
+	}
+	if strings.ContainsAny(redirectUri, "# *\t\r\n") {
+		return redirectUriErr("redirect uri must not contain fragment, whitespace or wildcard.")
+	}
+
+	u, err := url.Parse(redirectUri)
+	if err != nil {
+		return redirectUriErr("redirect uri format error.")
+	}
+	if u.Scheme == "" {
+		return redirectUriErr("redirect uri must be absolute.")
+	}
+	if u.User != nil {
+		return redirectUriErr("redirect uri must not contain user info.")
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		if u.Hostname() == "" {
+			return redirectUriErr("redirect uri host is required.")
+		}
+	case "http":
+		if !isLoopbackHost(u.Hostname()) {
+			return redirectUriErr("redirect uri must use https except for loopback.")
+		}
+	default:
+		// 自訂 scheme 須為反向網域，避免 javascript:、data:、file: 等
+		if !strings.Contains(u.Scheme, ".") {
+			return redirectUriErr("custom scheme must be a reverse domain name.")
+		}
+		if u.Host != "" {
+			return redirectUriErr("custom scheme redirect uri must not contain host.")
+		}
+	}
+
+	return nil
+}
+
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func redirectUriErr(msg string) error {
+	return er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, msg, nil)
+}
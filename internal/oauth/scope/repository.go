@@ -3,17 +3,43 @@ package scope
 import (
 	"fmt"
 	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/helper"
+	"time"
 )
 
 type Repository interface {
-	Count() (int, error)
-	Find(limit, offset int) ([]*model.OauthScope, error)
+	Count(filter *ListFilter) (int, error)
+	Find(filter *ListFilter, limit, offset int) ([]*model.OauthScope, error)
 	FindScope() ([]string, error)
 	FindOne(scope *model.OauthScope) (*model.OauthScope, error)
 	Insert(scope *model.OauthScope) error
 	Update(scope *model.OauthScope) error
 }
 
+// ListFilter 列表的搜尋條件，零值的欄位不限制
+type ListFilter struct {
+	Keyword     string // scope、name 或 path 包含
+	Method      string
+	IsDisable   *bool
+	Scope       string // 類別或完整 scope，類別包含其下所有項目
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Sort        []helper.SortField
+}
+
+// SortFields 可排序的欄位
+var SortFields = map[string]string{
+	"id":         "id",
+	"scope":      "scope",
+	"name":       "name",
+	"path":       "path",
+	"method":     "method",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
 type Cache interface {
 	DeleteOne(path, method string) error
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"oauth2-console-go/dto/model"
 	"oauth2-console-go/internal/oauth/scope"
+	"oauth2-console-go/pkg/helper"
 
 	"xorm.io/xorm"
 )
@@ -15,16 +16,32 @@ func NewRepository(orm *xorm.EngineGroup) scope.Repository {
 	return &Repository{orm: orm}
 }
 
-func (r *Repository) Count() (int, error) {
-	cnt, err := r.orm.Count(&model.OauthScope{})
+func (r *Repository) Count(filter *scope.ListFilter) (int, error) {
+	session := r.orm.Table("oauth_scope")
+	defer session.Close()
+
+	cnt, err := applyFilter(session, filter).Count()
 	return int(cnt), err
 }
 
-func (r *Repository) Find(limit, offset int) ([]*model.OauthScope, error) {
+func (r *Repository) Find(filter *scope.ListFilter, limit, offset int) ([]*model.OauthScope, error) {
 	var err error
 	scopes := make([]*model.OauthScope, 0)
 
-	err = r.orm.Limit(limit, offset).Find(&scopes)
+	session := r.orm.Table("oauth_scope")
+	defer session.Close()
+
+	session = applyFilter(session, filter)
+	for _, field := range filter.Sort {
+		if field.Desc {
+			session = session.Desc(field.Column)
+		} else {
+			session = session.Asc(field.Column)
+		}
+	}
+
+	// 以 id 排序確保分頁結果穩定
+	err = session.Asc("id").Limit(limit, offset).Find(&scopes)
 	if err != nil {
 		return nil, err
 	}
@@ -70,3 +87,33 @@ func (r *Repository) Update(scope *model.OauthScope) error {
 	_, err := r.orm.ID(scope.Id).Update(scope)
 	return err
 }
+
+func applyFilter(session *xorm.Session, filter *scope.ListFilter) *xorm.Session {
+	if filter.Keyword != "" {
+		keyword := "%" + helper.EscapeLike(filter.Keyword) + "%"
+		session = session.Where("(scope LIKE ? OR name LIKE ? OR path LIKE ?) ", keyword, keyword, keyword)
+	}
+	if filter.Method != "" {
+		session = session.Where("method = ? ", filter.Method)
+	}
+	if filter.IsDisable != nil {
+		session = session.Where("is_disable = ? ", *filter.IsDisable)
+	}
+	if filter.Scope != "" {
+		session = session.Where("(scope = ? OR scope LIKE ?) ", filter.Scope, helper.EscapeLike(filter.Scope)+".%")
+	}
+	if filter.CreatedFrom != nil {
+		session = session.Where("created_at >= ? ", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		session = session.Where("created_at <= ? ", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		session = session.Where("updated_at >= ? ", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		session = session.Where("updated_at <= ? ", *filter.UpdatedTo)
+	}
+
+	return session
+}
@@ -4,6 +4,8 @@ import (
 	"oauth2-console-go/config"
 	"oauth2-console-go/driver"
 	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/oauth/scope"
+	"oauth2-console-go/pkg/helper"
 	"oauth2-console-go/pkg/valider"
 	"os"
 	"testing"
@@ -29,7 +31,7 @@ func TestRepository_Count(t *testing.T) {
 	osr := NewRepository(orm)
 
 	// Act
-	total, err := osr.Count()
+	total, err := osr.Count(&scope.ListFilter{})
 
 	// Assert
 	assert.Nil(t, err)
@@ -42,7 +44,7 @@ func TestRepository_Find(t *testing.T) {
 	osr := NewRepository(orm)
 
 	// Act
-	scopes, err := osr.Find(10, 0)
+	scopes, err := osr.Find(&scope.ListFilter{}, 10, 0)
 
 	// Assert
 	assert.Nil(t, err)
@@ -50,6 +52,44 @@ func TestRepository_Find(t *testing.T) {
 	assert.Len(t, scopes, 4)
 }
 
+func TestRepository_FindFilter(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	osr := NewRepository(orm)
+
+	isDisable := true
+	testCases := []struct {
+		Name      string
+		Filter    scope.ListFilter
+		WantCount int
+		WantFirst string
+	}{
+		{"category", scope.ListFilter{Scope: "address-book"}, 3, "address-book.list_get"},
+		{"scope", scope.ListFilter{Scope: "user.profile_get"}, 1, "user.profile_get"},
+		{"keyword path", scope.ListFilter{Keyword: "contacts"}, 3, "address-book.list_get"},
+		{"keyword escape", scope.ListFilter{Keyword: "%"}, 0, ""},
+		{"method", scope.ListFilter{Method: "POST"}, 1, "address-book.contact_post"},
+		{"disabled", scope.ListFilter{IsDisable: &isDisable}, 0, ""},
+		{"sort", scope.ListFilter{Sort: []helper.SortField{{Column: "method", Desc: true}, {Column: "scope"}}}, 4, "address-book.contact_post"},
+		{"sort desc", scope.ListFilter{Sort: []helper.SortField{{Column: "id", Desc: true}}}, 4, "address-book.contact_get"},
+	}
+
+	for _, tc := range testCases {
+		// Act
+		list, err := osr.Find(&tc.Filter, 10, 0)
+		total, countErr := osr.Count(&tc.Filter)
+
+		// Assert
+		assert.Nil(t, err, tc.Name)
+		assert.Nil(t, countErr, tc.Name)
+		assert.Len(t, list, tc.WantCount, tc.Name)
+		assert.Equal(t, tc.WantCount, total, tc.Name)
+		if tc.WantFirst != "" && len(list) > 0 {
+			assert.Equal(t, tc.WantFirst, list[0].Scope, tc.Name)
+		}
+	}
+}
+
 func TestRepository_FindScope(t *testing.T) {
 	// Arrange
 	orm, _ := driver.NewXorm()
@@ -7,7 +7,7 @@ import (
 )
 
 type Service interface {
-	ListScope(sysAccId int, page, perPage int) (*apires.ListOauthScope, error)
+	ListScope(req *apireq.ListOauthScope) (*apires.ListOauthScope, error)
 	GetScope(sysAccId int, scopeId int) (*model.OauthScope, error)
 	AddScope(req *apireq.AddOauthScope) error
 	EditScope(scopeId int, req *apireq.EditOauthScope) error
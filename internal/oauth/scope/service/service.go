@@ -9,6 +9,7 @@ import (
 	"oauth2-console-go/internal/oauth/scope"
 	"oauth2-console-go/internal/system/sys_account"
 	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/helper"
 	"oauth2-console-go/pkg/logr"
 
 	"go.uber.org/zap"
@@ -30,9 +31,9 @@ func NewService(sar sys_account.Repository, osr scope.Repository, osc scope.Cach
 	}
 }
 
-func (s *Service) ListScope(sysAccId, page, perPage int) (*apires.ListOauthScope, error) {
+func (s *Service) ListScope(req *apireq.ListOauthScope) (*apires.ListOauthScope, error) {
 	// Check account id exist
-	sysAcc := model.SysAccount{Id: sysAccId}
+	sysAcc := model.SysAccount{Id: req.AccountId}
 	acc, err := s.sysAccRepo.FindOne(&sysAcc)
 	if err != nil {
 		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
@@ -43,23 +44,42 @@ func (s *Service) ListScope(sysAccId, page, perPage int) (*apires.ListOauthScope
 		return nil, notFoundErr
 	}
 
-	total, err := s.scopeRepo.Count()
+	sort, err := helper.ParseSort(req.Sort, scope.SortFields)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := scope.ListFilter{
+		Keyword:     req.Keyword,
+		Method:      req.Method,
+		IsDisable:   req.IsDisable,
+		Scope:       req.Scope,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		UpdatedFrom: req.UpdatedFrom,
+		UpdatedTo:   req.UpdatedTo,
+		Sort:        sort,
+	}
+
+	total, err := s.scopeRepo.Count(&filter)
 	if err != nil {
 		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "count scope error.", err)
 		return nil, unknownErr
 	}
 
+	page := req.Page
 	if page <= 1 {
 		page = 1
 	}
 
+	perPage := req.PerPage
 	if perPage <= 1 {
 		perPage = 1
 	}
 
 	offset := (page - 1) * perPage
 
-	list, err := s.scopeRepo.Find(perPage, offset)
+	list, err := s.scopeRepo.Find(&filter, perPage, offset)
 	if err != nil {
 		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find scope error.", err)
 		return nil, unknownErr
@@ -39,12 +39,22 @@ func TestService_ListScope(t *testing.T) {
 	oss := NewService(sar, osr, osc, occ)
 
 	// Act
-	res, err := oss.ListScope(1, 1, 10)
+	res, err := oss.ListScope(&apireq.ListOauthScope{AccountId: 1, Page: 1, PerPage: 10})
 
 	// Assert
 	assert.Nil(t, err)
 	assert.Equal(t, 4, res.Total)
 	assert.Len(t, res.List, 4)
+
+	// 依類別查詢並排序
+	res, err = oss.ListScope(&apireq.ListOauthScope{AccountId: 1, Page: 1, PerPage: 10, Scope: "address-book", Sort: "-scope"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, "address-book.list_get", res.List[0].Scope)
+
+	// 排序欄位不在清單內
+	_, err = oss.ListScope(&apireq.ListOauthScope{AccountId: 1, Page: 1, PerPage: 10, Sort: "description"})
+	assert.NotNil(t, err)
 }
 
 func TestService_GetScope(t *testing.T) {
@@ -0,0 +1,109 @@
+package library
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/helper"
+	"strings"
+)
+
+// api key 格式為 ock_<prefix>_<secret>，prefix 用於查詢，只儲存整組 key 的 hash
+const (
+	KeyPrefix  = "ock_"
+	prefixSize = 6  // 6 bytes = 12 個 hex 字元
+	secretSize = 32 // 32 bytes
+)
+
+// RouteGroups api key 可使用的路由群組，未列出的路由(登入、登出、api key 管理等)不開放 api key 使用
+var RouteGroups = map[string]string{
+	"oauth_client": "/v1/oauth/clients",
+	"oauth_scope":  "/v1/oauth/scopes",
+	"account":      "/v1/accounts",
+}
+
+func GenApiKey() (key, prefix string, err error) {
+	prefix, err = helper.RandomHex(prefixSize)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret, err := helper.RandomUrlSafe(secretSize)
+	if err != nil {
+		return "", "", err
+	}
+
+	return KeyPrefix + prefix + "_" + secret, prefix, nil
+}
+
+func ParseApiKey(key string) (prefix string, ok bool) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(key, KeyPrefix), "_", 2)
+	if len(parts) != 2 || len(parts[0]) != prefixSize*2 || parts[1] == "" {
+		return "", false
+	}
+
+	return parts[0], true
+}
+
+func HashApiKey(key string) string {
+	return helper.Sha256Str(key)
+}
+
+func CheckApiKey(m *model.SysAccountApiKey, key string) bool {
+	return subtle.ConstantTimeCompare([]byte(m.KeyHash), []byte(HashApiKey(key))) == 1
+}
+
+// GetRouteGroup 取得 request path 所屬的路由群組
+func GetRouteGroup(path string) string {
+	for group, prefix := range RouteGroups {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return group
+		}
+	}
+
+	return ""
+}
+
+func JoinRouteGroups(groups []string) string {
+	return strings.Join(groups, ",")
+}
+
+func SplitRouteGroups(groups string) []string {
+	list := make([]string, 0)
+	for _, group := range strings.Split(groups, ",") {
+		if group != "" {
+			list = append(list, group)
+		}
+	}
+
+	return list
+}
+
+// IsRequestAllowed 檢查 api key 的唯讀及路由群組限制
+func IsRequestAllowed(m *model.SysAccountApiKey, method, path string) bool {
+	if m.ReadOnly && method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions {
+		return false
+	}
+
+	group := GetRouteGroup(path)
+	if group == "" {
+		return false
+	}
+
+	groups := SplitRouteGroups(m.RouteGroups)
+	if len(groups) == 0 {
+		return true
+	}
+
+	for _, g := range groups {
+		if g == group {
+			return true
+		}
+	}
+
+	return false
+}
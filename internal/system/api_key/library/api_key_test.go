@@ -0,0 +1,68 @@
+package library
+
+import (
+	"net/http"
+	"oauth2-console-go/dto/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenApiKey(t *testing.T) {
+	// Act
+	key, prefix, err := GenApiKey()
+
+	// Assert
+	assert.Nil(t, err)
+	parsed, ok := ParseApiKey(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, parsed)
+
+	m := model.SysAccountApiKey{KeyHash: HashApiKey(key)}
+	assert.True(t, CheckApiKey(&m, key))
+	assert.False(t, CheckApiKey(&m, key+"x"))
+}
+
+func TestParseApiKey(t *testing.T) {
+	testCases := []struct {
+		Key    string
+		WantOk bool
+	}{
+		{"ock_0123456789ab_secret", true},
+		{"ock_0123456789ab_", false},
+		{"ock_0123_secret", false},
+		{"0123456789ab_secret", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		_, ok := ParseApiKey(tc.Key)
+		assert.Equal(t, tc.WantOk, ok, tc.Key)
+	}
+}
+
+func TestIsRequestAllowed(t *testing.T) {
+	all := model.SysAccountApiKey{}
+	readOnly := model.SysAccountApiKey{ReadOnly: true}
+	scopeOnly := model.SysAccountApiKey{RouteGroups: "oauth_scope"}
+
+	testCases := []struct {
+		Key    *model.SysAccountApiKey
+		Method string
+		Path   string
+		Want   bool
+	}{
+		{&all, http.MethodPost, "/v1/oauth/clients/", true},
+		{&all, http.MethodPost, "/v1/api-keys/", false},
+		{&all, http.MethodPost, "/v1/token/logout", false},
+		{&all, http.MethodGet, "/v1/oauth/clientsx", false},
+		{&readOnly, http.MethodGet, "/v1/oauth/clients/abc", true},
+		{&readOnly, http.MethodPut, "/v1/oauth/clients/abc", false},
+		{&scopeOnly, http.MethodGet, "/v1/oauth/scopes/1", true},
+		{&scopeOnly, http.MethodGet, "/v1/oauth/clients/abc", false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.Want, IsRequestAllowed(tc.Key, tc.Method, tc.Path), tc.Method+" "+tc.Path)
+	}
+}
@@ -0,0 +1,12 @@
+package api_key
+
+import "oauth2-console-go/dto/model"
+
+type Repository interface {
+	Insert(m *model.SysAccountApiKey) error
+	Find(sysAccId int) ([]*model.SysAccountApiKey, error)
+	FindOne(m *model.SysAccountApiKey) (*model.SysAccountApiKey, error)
+	Count(sysAccId int) (int, error)
+	Update(m *model.SysAccountApiKey, cols ...string) error
+	Delete(id int) error
+}
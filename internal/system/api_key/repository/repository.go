@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/api_key"
+
+	"xorm.io/xorm"
+)
+
+type Repository struct {
+	orm *xorm.EngineGroup
+}
+
+func NewRepository(orm *xorm.EngineGroup) api_key.Repository {
+	return &Repository{
+		orm: orm,
+	}
+}
+
+func (r *Repository) Insert(m *model.SysAccountApiKey) error {
+	_, err := r.orm.Insert(m)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) Find(sysAccId int) ([]*model.SysAccountApiKey, error) {
+	list := make([]*model.SysAccountApiKey, 0)
+
+	err := r.orm.Where("sys_account_id = ?", sysAccId).Desc("id").Find(&list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (r *Repository) FindOne(m *model.SysAccountApiKey) (*model.SysAccountApiKey, error) {
+	has, err := r.orm.Get(m)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, nil
+	}
+
+	return m, nil
+}
+
+func (r *Repository) Count(sysAccId int) (int, error) {
+	count, err := r.orm.Where("sys_account_id = ?", sysAccId).Count(&model.SysAccountApiKey{})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// Update 預設只更新非零值欄位，需要更新為零值時指定 cols
+func (r *Repository) Update(m *model.SysAccountApiKey, cols ...string) error {
+	session := r.orm.ID(m.Id)
+	if len(cols) > 0 {
+		session = session.Cols(cols...)
+	}
+
+	_, err := session.Update(m)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) Delete(id int) error {
+	_, err := r.orm.ID(id).Delete(&model.SysAccountApiKey{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
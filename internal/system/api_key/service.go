@@ -0,0 +1,14 @@
+package api_key
+
+import (
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+	"oauth2-console-go/dto/model"
+)
+
+type Service interface {
+	ListApiKey(req *apireq.ListApiKey) (*apires.ListApiKey, error)
+	AddApiKey(req *apireq.AddApiKey) (*apires.AddApiKey, error)
+	DeleteApiKey(sysAccId, apiKeyId int) error
+	Authenticate(key, ip string) (*model.SysAccountApiKey, *model.SysAccount, error)
+}
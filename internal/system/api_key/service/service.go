@@ -0,0 +1,219 @@
+package service
+
+import (
+	"net/http"
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/api_key"
+	apiKeyLibrary "oauth2-console-go/internal/system/api_key/library"
+	"oauth2-console-go/internal/system/sys_account"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/logr"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	sysAccRepo sys_account.Repository
+	apiKeyRepo api_key.Repository
+}
+
+func NewService(sar sys_account.Repository, akr api_key.Repository) api_key.Service {
+	return &Service{
+		sysAccRepo: sar,
+		apiKeyRepo: akr,
+	}
+}
+
+func (s *Service) ListApiKey(req *apireq.ListApiKey) (*apires.ListApiKey, error) {
+	err := s.checkAccount(req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.apiKeyRepo.Find(req.AccountId)
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find api key error.", err)
+		return nil, findErr
+	}
+
+	list := make([]*apires.ApiKey, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, genApiKeyRes(key))
+	}
+
+	res := apires.ListApiKey{
+		List: list,
+	}
+
+	return &res, nil
+}
+
+func (s *Service) AddApiKey(req *apireq.AddApiKey) (*apires.AddApiKey, error) {
+	err := s.checkAccount(req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpiredAt != nil && req.ExpiredAt.Before(time.Now()) {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "expired at must be in the future.", nil)
+		return nil, paramErr
+	}
+
+	total, err := s.apiKeyRepo.Count(req.AccountId)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "count api key error.", err)
+		return nil, unknownErr
+	}
+	if total >= config.ApiKeyMaxCount {
+		limitErr := er.NewAppErr(http.StatusBadRequest, er.LimitExceededError, "api key count limit exceeded.", nil)
+		return nil, limitErr
+	}
+
+	key, prefix, err := apiKeyLibrary.GenApiKey()
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "generate api key error.", err)
+		return nil, unknownErr
+	}
+
+	m := model.SysAccountApiKey{
+		SysAccountId: req.AccountId,
+		Name:         req.Name,
+		Prefix:       prefix,
+		KeyHash:      apiKeyLibrary.HashApiKey(key),
+		ReadOnly:     req.ReadOnly,
+		RouteGroups:  apiKeyLibrary.JoinRouteGroups(req.RouteGroups),
+	}
+	if req.ExpiredAt != nil {
+		m.ExpiredAt = req.ExpiredAt.UTC()
+	}
+
+	err = s.apiKeyRepo.Insert(&m)
+	if err != nil {
+		insertErr := er.NewAppErr(http.StatusInternalServerError, er.DBInsertError, "insert api key error.", err)
+		return nil, insertErr
+	}
+
+	res := apires.AddApiKey{
+		ApiKey: genApiKeyRes(&m),
+		Key:    key,
+	}
+
+	return &res, nil
+}
+
+func (s *Service) DeleteApiKey(sysAccId, apiKeyId int) error {
+	err := s.checkAccount(sysAccId)
+	if err != nil {
+		return err
+	}
+
+	key, err := s.apiKeyRepo.FindOne(&model.SysAccountApiKey{Id: apiKeyId, SysAccountId: sysAccId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find api key error.", err)
+		return findErr
+	}
+	if key == nil {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "api key not found.", nil)
+		return notFoundErr
+	}
+
+	err = s.apiKeyRepo.Delete(key.Id)
+	if err != nil {
+		deleteErr := er.NewAppErr(http.StatusInternalServerError, er.DBDeleteError, "delete api key error.", err)
+		return deleteErr
+	}
+
+	return nil
+}
+
+func (s *Service) Authenticate(key, ip string) (*model.SysAccountApiKey, *model.SysAccount, error) {
+	prefix, ok := apiKeyLibrary.ParseApiKey(key)
+	if !ok {
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "api key is not valid.", nil)
+		return nil, nil, authErr
+	}
+
+	m, err := s.apiKeyRepo.FindOne(&model.SysAccountApiKey{Prefix: prefix})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find api key error.", err)
+		return nil, nil, findErr
+	}
+	if m == nil || !apiKeyLibrary.CheckApiKey(m, key) {
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "api key is not valid.", nil)
+		return nil, nil, authErr
+	}
+
+	now := time.Now().UTC()
+	if !m.ExpiredAt.IsZero() && m.ExpiredAt.Before(now) {
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "api key is expired.", nil)
+		return nil, nil, authErr
+	}
+
+	// Check account exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: m.SysAccountId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, nil, findErr
+	}
+	if acc == nil {
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "api key is not valid.", nil)
+		return nil, nil, authErr
+	}
+	if acc.IsDisable {
+		disabledErr := er.NewAppErr(http.StatusUnauthorized, er.AccountDisabledError, "account is disabled.", nil)
+		return nil, nil, disabledErr
+	}
+
+	// 降低寫入頻率，最後使用時間每分鐘更新一次
+	if now.Sub(m.LastUsedAt) > time.Minute || m.LastUsedIp != ip {
+		m.LastUsedAt = now
+		m.LastUsedIp = ip
+		err = s.apiKeyRepo.Update(m, "last_used_at", "last_used_ip")
+		if err != nil {
+			logr.L.Error("update api key last used error.", zap.String("error", err.Error()))
+		}
+	}
+
+	return m, acc, nil
+}
+
+func (s *Service) checkAccount(sysAccId int) error {
+	// Check account id exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: sysAccId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+	if acc == nil || acc.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return notFoundErr
+	}
+
+	return nil
+}
+
+func genApiKeyRes(m *model.SysAccountApiKey) *apires.ApiKey {
+	res := apires.ApiKey{
+		Id:          m.Id,
+		Name:        m.Name,
+		Prefix:      m.Prefix,
+		ReadOnly:    m.ReadOnly,
+		RouteGroups: apiKeyLibrary.SplitRouteGroups(m.RouteGroups),
+		LastUsedIp:  m.LastUsedIp,
+		CreatedAt:   m.CreatedAt,
+	}
+	if !m.ExpiredAt.IsZero() {
+		expiredAt := m.ExpiredAt
+		res.ExpiredAt = &expiredAt
+	}
+	if !m.LastUsedAt.IsZero() {
+		lastUsedAt := m.LastUsedAt
+		res.LastUsedAt = &lastUsedAt
+	}
+
+	return &res
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"net/http"
+	"oauth2-console-go/config"
+	"oauth2-console-go/driver"
+	"oauth2-console-go/dto/apireq"
+	apiKeyRepo "oauth2-console-go/internal/system/api_key/repository"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setUp()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func setUp() {
+	config.InitEnv()
+	valider.Init()
+}
+
+func TestService_AddApiKey(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := sysAccRepo.NewRepository(orm)
+	akr := apiKeyRepo.NewRepository(orm)
+	aks := NewService(sar, akr)
+
+	req := apireq.AddApiKey{
+		AccountId:   1,
+		Name:        "ci pipeline",
+		ReadOnly:    true,
+		RouteGroups: []string{"oauth_client"},
+	}
+
+	// Act
+	res, err := aks.AddApiKey(&req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.Key)
+	assert.Equal(t, []string{"oauth_client"}, res.RouteGroups)
+
+	// Authenticate
+	key, acc, err := aks.Authenticate(res.Key, "127.0.0.1")
+	assert.Nil(t, err)
+	assert.Equal(t, req.AccountId, key.SysAccountId)
+	assert.Equal(t, req.AccountId, acc.Id)
+	assert.True(t, key.ReadOnly)
+
+	_, _, err = aks.Authenticate(res.Key+"x", "127.0.0.1")
+	authErr := err.(*er.AppError)
+	assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+
+	// List
+	list, err := aks.ListApiKey(&apireq.ListApiKey{AccountId: req.AccountId})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, list.List)
+
+	// Teardown
+	err = aks.DeleteApiKey(req.AccountId, res.Id)
+	assert.Nil(t, err)
+	_, _, err = aks.Authenticate(res.Key, "127.0.0.1")
+	assert.NotNil(t, err)
+}
+
+func TestService_AddApiKey_Expired(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := sysAccRepo.NewRepository(orm)
+	akr := apiKeyRepo.NewRepository(orm)
+	aks := NewService(sar, akr)
+
+	expiredAt := time.Now().Add(-time.Hour)
+	req := apireq.AddApiKey{
+		AccountId: 1,
+		Name:      "expired",
+		ExpiredAt: &expiredAt,
+	}
+
+	// Act
+	res, err := aks.AddApiKey(&req)
+
+	// Assert
+	assert.Nil(t, res)
+	paramErr := err.(*er.AppError)
+	assert.Equal(t, strconv.Itoa(er.ErrorParamInvalid), paramErr.Code)
+}
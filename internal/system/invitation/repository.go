@@ -0,0 +1,17 @@
+package invitation
+
+import (
+	"fmt"
+	"oauth2-console-go/dto/model"
+	"time"
+)
+
+type Cache interface {
+	GetInvitation(tokenHash string) (*model.Invitation, error)
+	SetInvitation(inv *model.Invitation, expiration time.Duration) error
+	DeleteInvitation(tokenHash string) (bool, error)
+}
+
+func GetInvitationRedisKey(tokenHash string) string {
+	return fmt.Sprintf("invitation:%s", tokenHash)
+}
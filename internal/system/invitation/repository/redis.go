@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"encoding/json"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/invitation"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+type Cache struct {
+	redisCluster *redis.ClusterClient
+}
+
+func NewRedis(rc *redis.ClusterClient) invitation.Cache {
+	return &Cache{
+		redisCluster: rc,
+	}
+}
+
+func (c *Cache) GetInvitation(tokenHash string) (*model.Invitation, error) {
+	key := invitation.GetInvitationRedisKey(tokenHash)
+	str, err := c.redisCluster.Get(key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inv := model.Invitation{}
+	err = json.Unmarshal([]byte(str), &inv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inv, nil
+}
+
+func (c *Cache) SetInvitation(inv *model.Invitation, expiration time.Duration) error {
+	key := invitation.GetInvitationRedisKey(inv.TokenHash)
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.Set(key, string(data), expiration).Err()
+	return err
+}
+
+// DeleteInvitation 回傳是否由此次呼叫刪除，用於確保邀請只能使用一次
+func (c *Cache) DeleteInvitation(tokenHash string) (bool, error) {
+	cnt, err := c.redisCluster.Del(invitation.GetInvitationRedisKey(tokenHash)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return cnt > 0, nil
+}
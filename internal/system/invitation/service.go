@@ -0,0 +1,11 @@
+package invitation
+
+import (
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+)
+
+type Service interface {
+	AddInvitation(req *apireq.AddInvitation) (*apires.Invitation, error)
+	AcceptInvitation(req *apireq.AcceptInvitation) (*apires.SysAccount, error)
+}
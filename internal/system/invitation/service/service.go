@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/invitation"
+	"oauth2-console-go/internal/system/sys_account"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/helper"
+	"oauth2-console-go/pkg/logr"
+	"oauth2-console-go/pkg/mailer"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	sysAccRepo      sys_account.Repository
+	invitationCache invitation.Cache
+	mailer          mailer.Mailer
+}
+
+func NewService(sar sys_account.Repository, ic invitation.Cache, m mailer.Mailer) invitation.Service {
+	return &Service{
+		sysAccRepo:      sar,
+		invitationCache: ic,
+		mailer:          m,
+	}
+}
+
+func (s *Service) AddInvitation(req *apireq.AddInvitation) (*apires.Invitation, error) {
+	// Check account id exist
+	inviter, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: req.AccountId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if inviter == nil || inviter.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return nil, notFoundErr
+	}
+
+	// Check email unique
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Email: req.Email})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc != nil {
+		duplicateErr := er.NewAppErr(http.StatusBadRequest, er.DataDuplicateError, "email duplicate error.", nil)
+		return nil, duplicateErr
+	}
+
+	inviteToken, err := helper.RandomUrlSafe(32)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "generate invitation token error.", err)
+		return nil, unknownErr
+	}
+
+	// Redis 只保存 hash
+	inv := model.Invitation{
+		TokenHash: helper.Sha256Str(inviteToken),
+		Email:     req.Email,
+		Role:      req.Role,
+		InvitedBy: inviter.Id,
+		ExpiredAt: time.Now().UTC().Add(config.InvitationExpireTime),
+	}
+	err = s.invitationCache.SetInvitation(&inv, config.InvitationExpireTime)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "set invitation error.", err)
+		return nil, redisErr
+	}
+
+	if s.mailer == nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "mailer is not configured.", nil)
+		return nil, unknownErr
+	}
+
+	link := fmt.Sprintf("%s/accept-invitation?token=%s", config.GetConsoleUrl(), url.QueryEscape(inviteToken))
+	err = s.mailer.Send(&mailer.Message{
+		To:      []string{req.Email},
+		Subject: "You are invited to the console",
+		Body: fmt.Sprintf("Hi,\n\n%s invited you to join the console as %s. Use the link below to create your account. The link expires in %d hours.\n\n%s\n",
+			inviter.Name, req.Role, int(config.InvitationExpireTime.Hours()), link),
+	})
+	if err != nil {
+		logr.L.Error("send invitation mail error.", zap.String("error", err.Error()))
+		_, _ = s.invitationCache.DeleteInvitation(inv.TokenHash)
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "send mail error.", err)
+		return nil, unknownErr
+	}
+
+	res := apires.Invitation{
+		Email:     inv.Email,
+		Role:      inv.Role,
+		ExpiredAt: inv.ExpiredAt,
+	}
+
+	return &res, nil
+}
+
+func (s *Service) AcceptInvitation(req *apireq.AcceptInvitation) (*apires.SysAccount, error) {
+	tokenHash := helper.Sha256Str(req.Token)
+	inv, err := s.invitationCache.GetInvitation(tokenHash)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get invitation error.", err)
+		return nil, redisErr
+	}
+	if inv == nil || inv.ExpiredAt.Before(time.Now().UTC()) {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "invitation token is not valid.", nil)
+		return nil, paramErr
+	}
+
+	// Check account unique
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Account: req.Account})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc != nil {
+		duplicateErr := er.NewAppErr(http.StatusBadRequest, er.DataDuplicateError, "account duplicate error.", nil)
+		return nil, duplicateErr
+	}
+
+	pw, err := helper.HashPassword(req.Password)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "hash password error.", err)
+		return nil, unknownErr
+	}
+
+	// 邀請只能使用一次，同時送出時只有一個 request 能取得
+	ok, err := s.invitationCache.DeleteInvitation(tokenHash)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete invitation error.", err)
+		return nil, redisErr
+	}
+	if !ok {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "invitation token is not valid.", nil)
+		return nil, paramErr
+	}
+
+	// 邀請信寄至受邀者的 email，建立時視為已驗證
+	m := model.SysAccount{
+		Account:  req.Account,
+		Password: pw,
+		Name:     req.Name,
+		Email:    inv.Email,
+		Phone:    req.Phone,
+		Role:     inv.Role,
+		VerifyAt: time.Now().UTC(),
+	}
+
+	err = s.sysAccRepo.Insert(&m)
+	if err != nil {
+		// 建立失敗時還原邀請，讓受邀者可以再次使用
+		if ttl := time.Until(inv.ExpiredAt); ttl > 0 {
+			_ = s.invitationCache.SetInvitation(inv, ttl)
+		}
+		insertErr := er.NewAppErr(http.StatusInternalServerError, er.DBInsertError, "insert account error.", err)
+		return nil, insertErr
+	}
+
+	return apires.NewSysAccount(&m), nil
+}
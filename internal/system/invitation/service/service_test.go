@@ -0,0 +1,92 @@
+package service
+
+import (
+	"net/url"
+	"oauth2-console-go/config"
+	"oauth2-console-go/driver"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/model"
+	invitationRepo "oauth2-console-go/internal/system/invitation/repository"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	"oauth2-console-go/pkg/helper"
+	"oauth2-console-go/pkg/mailer"
+	"oauth2-console-go/pkg/valider"
+	"os"
+	"regexp"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setUp()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func setUp() {
+	config.InitEnv()
+	valider.Init()
+}
+
+func TestService_AcceptInvitation(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	ic := invitationRepo.NewRedis(rc)
+	outbox := mailer.NewOutbox("", "no-reply@example.com")
+	is := NewService(sar, ic, outbox)
+
+	req := apireq.AddInvitation{
+		AccountId: 1,
+		Email:     "invitee@email.com",
+		Role:      model.RoleEditor,
+	}
+
+	// Act
+	inv, err := is.AddInvitation(&req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, req.Email, inv.Email)
+	assert.Len(t, outbox.Messages(), 1)
+	assert.Equal(t, []string{req.Email}, outbox.Last().To)
+
+	match := regexp.MustCompile(`token=([^\s]+)`).FindStringSubmatch(outbox.Last().Body)
+	assert.Len(t, match, 2)
+	inviteToken, _ := url.QueryUnescape(match[1])
+
+	acceptReq := apireq.AcceptInvitation{
+		Token:    inviteToken,
+		Account:  "invitee_account",
+		Password: "A12345678",
+		Name:     "invitee",
+	}
+
+	_, err = is.AcceptInvitation(&apireq.AcceptInvitation{Token: "invalid-token", Account: acceptReq.Account, Password: acceptReq.Password, Name: acceptReq.Name})
+	assert.NotNil(t, err)
+
+	res, err := is.AcceptInvitation(&acceptReq)
+	assert.Nil(t, err)
+	assert.Equal(t, req.Email, res.Email)
+	assert.Equal(t, model.RoleEditor, res.Role)
+	assert.NotNil(t, res.VerifyAt)
+
+	acc, _ := sar.FindOne(&model.SysAccount{Id: res.Id})
+	ok, _ := helper.VerifyPassword(acc.Password, acceptReq.Password)
+	assert.True(t, ok)
+
+	// 邀請只能使用一次
+	acceptReq.Account = "invitee_account_2"
+	_, err = is.AcceptInvitation(&acceptReq)
+	assert.NotNil(t, err)
+
+	// Email 已被使用
+	_, err = is.AddInvitation(&req)
+	assert.NotNil(t, err)
+
+	// Teardown
+	_, _ = orm.ID(res.Id).Delete(&model.SysAccount{})
+}
@@ -0,0 +1,10 @@
+package login_log
+
+import "oauth2-console-go/dto/model"
+
+type Repository interface {
+	Insert(m *model.SysAccountLoginLog) error
+	Find(sysAccId, limit, offset int) ([]*model.SysAccountLoginLog, error)
+	FindLastSuccess(sysAccId int) (*model.SysAccountLoginLog, error)
+	Count(sysAccId int) (int, error)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/login_log"
+
+	"xorm.io/xorm"
+)
+
+type Repository struct {
+	orm *xorm.EngineGroup
+}
+
+func NewRepository(orm *xorm.EngineGroup) login_log.Repository {
+	return &Repository{
+		orm: orm,
+	}
+}
+
+func (r *Repository) Insert(m *model.SysAccountLoginLog) error {
+	_, err := r.orm.Insert(m)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) Find(sysAccId, limit, offset int) ([]*model.SysAccountLoginLog, error) {
+	list := make([]*model.SysAccountLoginLog, 0)
+
+	err := r.orm.Where("sys_account_id = ?", sysAccId).Desc("id").Limit(limit, offset).Find(&list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (r *Repository) FindLastSuccess(sysAccId int) (*model.SysAccountLoginLog, error) {
+	m := model.SysAccountLoginLog{}
+	has, err := r.orm.Where("sys_account_id = ? AND is_success = ?", sysAccId, true).Desc("id").Get(&m)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, nil
+	}
+
+	return &m, nil
+}
+
+func (r *Repository) Count(sysAccId int) (int, error) {
+	count, err := r.orm.Where("sys_account_id = ?", sysAccId).Count(&model.SysAccountLoginLog{})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
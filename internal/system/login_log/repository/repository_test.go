@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"oauth2-console-go/config"
+	"oauth2-console-go/driver"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/valider"
+	"os"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setUp()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func setUp() {
+	config.InitEnv()
+	valider.Init()
+}
+
+func TestRepository_Insert(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	llr := NewRepository(orm)
+
+	m := model.SysAccountLoginLog{
+		SysAccountId: 1,
+		Account:      "sys_account",
+		Ip:           "127.0.0.1",
+		UserAgent:    "test_user_agent",
+		IsSuccess:    true,
+	}
+
+	// Act
+	err := llr.Insert(&m)
+
+	// Assert
+	assert.Nil(t, err)
+
+	last, err := llr.FindLastSuccess(m.SysAccountId)
+	assert.Nil(t, err)
+	assert.Equal(t, m.Id, last.Id)
+
+	// Teardown
+	_, _ = orm.ID(m.Id).Delete(&model.SysAccountLoginLog{})
+}
+
+func TestRepository_Find(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	llr := NewRepository(orm)
+
+	m := model.SysAccountLoginLog{
+		SysAccountId: 1,
+		Account:      "sys_account",
+		Reason:       model.LoginReasonPasswordMismatch,
+	}
+	_ = llr.Insert(&m)
+
+	// Act
+	total, err := llr.Count(m.SysAccountId)
+	list, err2 := llr.Find(m.SysAccountId, 1, 0)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, err2)
+	assert.GreaterOrEqual(t, total, 1)
+	assert.Len(t, list, 1)
+	assert.Equal(t, m.Id, list[0].Id)
+
+	// Teardown
+	_, _ = orm.ID(m.Id).Delete(&model.SysAccountLoginLog{})
+}
@@ -0,0 +1,10 @@
+package login_log
+
+import (
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+)
+
+type Service interface {
+	ListLoginLog(req *apireq.ListLoginLog) (*apires.ListLoginLog, error)
+}
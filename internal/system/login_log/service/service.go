@@ -0,0 +1,69 @@
+package service
+
+import (
+	"net/http"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/login_log"
+	"oauth2-console-go/internal/system/sys_account"
+	"oauth2-console-go/pkg/er"
+)
+
+type Service struct {
+	sysAccRepo   sys_account.Repository
+	loginLogRepo login_log.Repository
+}
+
+func NewService(sar sys_account.Repository, llr login_log.Repository) login_log.Service {
+	return &Service{
+		sysAccRepo:   sar,
+		loginLogRepo: llr,
+	}
+}
+
+func (s *Service) ListLoginLog(req *apireq.ListLoginLog) (*apires.ListLoginLog, error) {
+	// Check account id exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: req.SysAccountId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc == nil {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return nil, notFoundErr
+	}
+
+	total, err := s.loginLogRepo.Count(req.SysAccountId)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "count login log error.", err)
+		return nil, unknownErr
+	}
+
+	page := req.Page
+	if page <= 1 {
+		page = 1
+	}
+
+	perPage := req.PerPage
+	if perPage <= 1 {
+		perPage = 1
+	}
+
+	offset := (page - 1) * perPage
+
+	list, err := s.loginLogRepo.Find(req.SysAccountId, perPage, offset)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find login log error.", err)
+		return nil, unknownErr
+	}
+
+	res := apires.ListLoginLog{
+		List:        list,
+		Total:       total,
+		CurrentPage: page,
+		PerPage:     perPage,
+	}
+
+	return &res, nil
+}
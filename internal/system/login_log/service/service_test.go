@@ -0,0 +1,60 @@
+package service
+
+import (
+	"oauth2-console-go/config"
+	"oauth2-console-go/driver"
+	"oauth2-console-go/dto/apireq"
+	loginLogRepo "oauth2-console-go/internal/system/login_log/repository"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	"oauth2-console-go/pkg/valider"
+	"os"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setUp()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func setUp() {
+	config.InitEnv()
+	valider.Init()
+}
+
+func TestService_ListLoginLog(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	lls := NewService(sar, llr)
+
+	// No data
+	req := apireq.ListLoginLog{
+		AccountId:    1,
+		SysAccountId: 999999,
+		Page:         1,
+		PerPage:      10,
+	}
+
+	// Act
+	res, err := lls.ListLoginLog(&req)
+
+	// Assert
+	assert.NotNil(t, err)
+	assert.Nil(t, res)
+
+	// Has data
+	req.SysAccountId = 1
+
+	// Act
+	res, err = lls.ListLoginLog(&req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, res.CurrentPage)
+	assert.LessOrEqual(t, len(res.List), 10)
+}
@@ -0,0 +1,96 @@
+package library
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/helper"
+	"oauth2-console-go/pkg/totp"
+	"strings"
+	"time"
+)
+
+const (
+	RecoveryCodeCount = 10
+	recoveryCodeSize  = 5 // 5 bytes = 10 個 hex 字元
+)
+
+// GenRecoveryCodes 產生 recovery codes，回傳明碼(僅顯示一次)及存入 DB 的 hash 清單
+func GenRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	hashes := make([]string, 0, RecoveryCodeCount)
+
+	for i := 0; i < RecoveryCodeCount; i++ {
+		str, err := helper.RandomHex(recoveryCodeSize)
+		if err != nil {
+			return nil, "", err
+		}
+
+		codes = append(codes, str[:5]+"-"+str[5:])
+		hashes = append(hashes, helper.Sha256Str(str))
+	}
+
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return codes, string(data), nil
+}
+
+// UseRecoveryCode 比對 recovery code，成功時回傳移除該 code 後的 hash 清單
+func UseRecoveryCode(stored, code string) (string, bool) {
+	hashes := make([]string, 0)
+	if json.Unmarshal([]byte(stored), &hashes) != nil {
+		return stored, false
+	}
+
+	hash := helper.Sha256Str(normalizeRecoveryCode(code))
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) != 1 {
+			continue
+		}
+
+		remaining := append(hashes[:i:i], hashes[i+1:]...)
+		data, err := json.Marshal(remaining)
+		if err != nil {
+			return stored, false
+		}
+		return string(data), true
+	}
+
+	return stored, false
+}
+
+// VerifyCode 驗證 TOTP code 或 recovery code
+// 使用 TOTP code 時回傳符合的時間區間 counter，須以 UpdateTotpCounter 記錄；使用 recovery code 時 isRecovery 為 true 並回傳剩餘的 hash 清單
+func VerifyCode(acc *model.SysAccount, code string, t time.Time) (ok bool, isRecovery bool, counter int64, recoveryCodes string) {
+	if acc.TotpSecret == "" {
+		return false, false, 0, acc.MfaRecoveryCodes
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		counter, ok = VerifyTotp(acc, code, t)
+		return ok, false, counter, acc.MfaRecoveryCodes
+	}
+
+	recoveryCodes, ok = UseRecoveryCode(acc.MfaRecoveryCodes, code)
+	return ok, ok, 0, recoveryCodes
+}
+
+// VerifyTotp 驗證 TOTP code，已使用過的時間區間(含誤差範圍內)不可再次使用
+func VerifyTotp(acc *model.SysAccount, code string, t time.Time) (int64, bool) {
+	counter, ok := totp.ValidateCounter(acc.TotpSecret, code, t)
+	if !ok || counter <= acc.TotpLastCounter {
+		return 0, false
+	}
+
+	return counter, true
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
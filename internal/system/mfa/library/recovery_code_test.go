@@ -0,0 +1,74 @@
+package library
+
+import (
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/totp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenRecoveryCodes(t *testing.T) {
+	// Act
+	codes, stored, err := GenRecoveryCodes()
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, codes, RecoveryCodeCount)
+	for _, code := range codes {
+		assert.Len(t, code, 11)
+		assert.False(t, strings.Contains(stored, code))
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	// Arrange
+	codes, stored, _ := GenRecoveryCodes()
+
+	// Act
+	remaining, ok := UseRecoveryCode(stored, strings.ToUpper(codes[0]))
+
+	// Assert
+	assert.True(t, ok)
+	assert.NotEqual(t, stored, remaining)
+
+	// 同一組 code 只能使用一次
+	_, ok = UseRecoveryCode(remaining, codes[0])
+	assert.False(t, ok)
+
+	// 其他 code 仍可使用
+	_, ok = UseRecoveryCode(remaining, codes[1])
+	assert.True(t, ok)
+}
+
+func TestVerifyCode(t *testing.T) {
+	// Arrange
+	secret, _ := totp.GenerateSecret()
+	codes, stored, _ := GenRecoveryCodes()
+	acc := model.SysAccount{TotpSecret: secret, MfaRecoveryCodes: stored}
+	now := time.Now()
+	code, _ := totp.Code(secret, now)
+
+	// TOTP code
+	ok, isRecovery, counter, _ := VerifyCode(&acc, code, now)
+	assert.True(t, ok)
+	assert.False(t, isRecovery)
+	assert.Equal(t, now.Unix()/totp.Period, counter)
+
+	// Same TOTP code again
+	acc.TotpLastCounter = counter
+	ok, _, _, _ = VerifyCode(&acc, code, now)
+	assert.False(t, ok)
+
+	// Recovery code
+	ok, isRecovery, _, remaining := VerifyCode(&acc, codes[2], now)
+	assert.True(t, ok)
+	assert.True(t, isRecovery)
+	assert.NotEqual(t, stored, remaining)
+
+	// Wrong code
+	ok, _, _, _ = VerifyCode(&acc, "000000-x", now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,12 @@
+package mfa
+
+import (
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+)
+
+type Service interface {
+	EnrollTotp(req *apireq.EnrollMfaTotp) (*apires.MfaTotpEnroll, error)
+	ActivateTotp(req *apireq.ActivateMfaTotp) error
+	DisableTotp(req *apireq.DisableMfaTotp) error
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"net/http"
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/mfa"
+	mfaLibrary "oauth2-console-go/internal/system/mfa/library"
+	"oauth2-console-go/internal/system/sys_account"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/totp"
+	"time"
+)
+
+type Service struct {
+	sysAccRepo sys_account.Repository
+}
+
+func NewService(sar sys_account.Repository) mfa.Service {
+	return &Service{
+		sysAccRepo: sar,
+	}
+}
+
+func (s *Service) EnrollTotp(req *apireq.EnrollMfaTotp) (*apires.MfaTotpEnroll, error) {
+	acc, err := s.findAccount(req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if acc.MfaEnabled {
+		duplicateErr := er.NewAppErr(http.StatusBadRequest, er.DataDuplicateError, "mfa already enabled.", nil)
+		return nil, duplicateErr
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "generate totp secret error.", err)
+		return nil, unknownErr
+	}
+
+	codes, recoveryCodes, err := mfaLibrary.GenRecoveryCodes()
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "generate recovery codes error.", err)
+		return nil, unknownErr
+	}
+
+	// 尚未啟用，需以 authenticator app 產生的 code 驗證後才生效
+	acc.TotpSecret = secret
+	acc.MfaRecoveryCodes = recoveryCodes
+	err = s.sysAccRepo.Update(acc, "totp_secret", "mfa_recovery_codes")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return nil, updateErr
+	}
+
+	res := apires.MfaTotpEnroll{
+		Secret:        secret,
+		KeyUri:        totp.KeyUri(config.GetMfaIssuer(), acc.Account, secret),
+		RecoveryCodes: codes,
+	}
+
+	return &res, nil
+}
+
+func (s *Service) ActivateTotp(req *apireq.ActivateMfaTotp) error {
+	acc, err := s.findAccount(req.AccountId)
+	if err != nil {
+		return err
+	}
+	if acc.MfaEnabled {
+		duplicateErr := er.NewAppErr(http.StatusBadRequest, er.DataDuplicateError, "mfa already enabled.", nil)
+		return duplicateErr
+	}
+	if acc.TotpSecret == "" {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "totp not enrolled.", nil)
+		return paramErr
+	}
+
+	counter, ok := mfaLibrary.VerifyTotp(acc, req.Code, time.Now())
+	if !ok {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "mfa code is not valid.", nil)
+		return paramErr
+	}
+
+	err = s.useTotpCounter(acc.Id, counter)
+	if err != nil {
+		return err
+	}
+
+	acc.MfaEnabled = true
+	err = s.sysAccRepo.Update(acc, "mfa_enabled")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return updateErr
+	}
+
+	return nil
+}
+
+func (s *Service) DisableTotp(req *apireq.DisableMfaTotp) error {
+	acc, err := s.findAccount(req.AccountId)
+	if err != nil {
+		return err
+	}
+	if acc.MfaRequired {
+		forbiddenErr := er.NewAppErr(http.StatusForbidden, er.ForbiddenError, "mfa is required for this account.", nil)
+		return forbiddenErr
+	}
+	if !acc.MfaEnabled {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "mfa not enabled.", nil)
+		return notFoundErr
+	}
+
+	// 停用前需再次驗證 TOTP code 或 recovery code
+	ok, isRecovery, counter, _ := mfaLibrary.VerifyCode(acc, req.Code, time.Now())
+	if !ok {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "mfa code is not valid.", nil)
+		return paramErr
+	}
+	if !isRecovery {
+		err = s.useTotpCounter(acc.Id, counter)
+		if err != nil {
+			return err
+		}
+	}
+
+	acc.TotpSecret = ""
+	acc.MfaRecoveryCodes = ""
+	acc.MfaEnabled = false
+	err = s.sysAccRepo.Update(acc, "totp_secret", "mfa_recovery_codes", "mfa_enabled")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return updateErr
+	}
+
+	return nil
+}
+
+// useTotpCounter 記錄已使用的 TOTP 時間區間，並行請求使用同一個 code 時只有一個會成功
+func (s *Service) useTotpCounter(accId int, counter int64) error {
+	ok, err := s.sysAccRepo.UpdateTotpCounter(accId, counter)
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return updateErr
+	}
+	if !ok {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "mfa code is not valid.", nil)
+		return paramErr
+	}
+
+	return nil
+}
+
+func (s *Service) findAccount(accId int) (*model.SysAccount, error) {
+	// Check account id exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc == nil || acc.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return nil, notFoundErr
+	}
+
+	return acc, nil
+}
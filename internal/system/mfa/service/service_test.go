@@ -0,0 +1,64 @@
+package service
+
+import (
+	"oauth2-console-go/config"
+	"oauth2-console-go/driver"
+	"oauth2-console-go/dto/apireq"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	"oauth2-console-go/pkg/totp"
+	"oauth2-console-go/pkg/valider"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setUp()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func setUp() {
+	config.InitEnv()
+	valider.Init()
+}
+
+func TestService_EnrollTotp(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := sysAccRepo.NewRepository(orm)
+	ms := NewService(sar)
+
+	accId := 1
+
+	// Act
+	res, err := ms.EnrollTotp(&apireq.EnrollMfaTotp{AccountId: accId})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.Secret)
+	assert.Contains(t, res.KeyUri, "otpauth://totp/")
+	assert.Len(t, res.RecoveryCodes, 10)
+
+	// Activate with wrong code
+	err = ms.ActivateTotp(&apireq.ActivateMfaTotp{AccountId: accId, Code: "000000"})
+	code, _ := totp.Code(res.Secret, time.Now())
+	if code != "000000" {
+		assert.NotNil(t, err)
+	}
+
+	// Activate
+	err = ms.ActivateTotp(&apireq.ActivateMfaTotp{AccountId: accId, Code: code})
+	assert.Nil(t, err)
+
+	// Enroll again
+	_, err = ms.EnrollTotp(&apireq.EnrollMfaTotp{AccountId: accId})
+	assert.NotNil(t, err)
+
+	// Teardown
+	err = ms.DisableTotp(&apireq.DisableMfaTotp{AccountId: accId, Code: res.RecoveryCodes[0]})
+	assert.Nil(t, err)
+}
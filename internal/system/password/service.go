@@ -0,0 +1,8 @@
+package password
+
+import "oauth2-console-go/dto/apireq"
+
+type Service interface {
+	ForgotPassword(req *apireq.ForgotPassword) error
+	ResetPassword(req *apireq.ResetPassword) error
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/password"
+	"oauth2-console-go/internal/system/sys_account"
+	"oauth2-console-go/internal/token"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/helper"
+	"oauth2-console-go/pkg/logr"
+	"oauth2-console-go/pkg/mailer"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	sysAccRepo   sys_account.Repository
+	tokenService token.Service
+	mailer       mailer.Mailer
+}
+
+func NewService(sar sys_account.Repository, ts token.Service, m mailer.Mailer) password.Service {
+	return &Service{
+		sysAccRepo:   sar,
+		tokenService: ts,
+		mailer:       m,
+	}
+}
+
+func (s *Service) ForgotPassword(req *apireq.ForgotPassword) error {
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Email: req.Email})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+
+	// 帳號不存在時同樣回傳成功，避免被用來探測 email
+	if acc == nil || acc.IsDisable {
+		return nil
+	}
+
+	// 重寄間隔內不再寄送
+	now := time.Now().UTC()
+	if acc.ForgotPassToken != "" && now.Before(acc.ForgotPassTokenExpiredAt.Add(-config.ForgotPassExpireTime+config.ForgotPassResendTime)) {
+		return nil
+	}
+
+	resetToken, err := helper.RandomUrlSafe(32)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "generate reset token error.", err)
+		return unknownErr
+	}
+
+	// 資料庫只保存 hash，新的 token 會取代尚未使用的舊 token
+	acc.ForgotPassToken = helper.Sha256Str(resetToken)
+	acc.ForgotPassTokenExpiredAt = now.Add(config.ForgotPassExpireTime)
+	err = s.sysAccRepo.Update(acc, "forgot_pass_token", "forgot_pass_token_expired_at")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return updateErr
+	}
+
+	// 寄信失敗同樣回傳成功，避免只有帳號存在時才回傳錯誤而被用來探測 email
+	if s.mailer == nil {
+		logr.L.Error("send forgot password mail error.", zap.String("error", "mailer is not configured"))
+		return nil
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", config.GetConsoleUrl(), url.QueryEscape(resetToken))
+	err = s.mailer.Send(&mailer.Message{
+		To:      []string{acc.Email},
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to reset your password. The link expires in %d minutes.\n\n%s\n\nIf you did not request a password reset, you can ignore this email.\n",
+			acc.Name, int(config.ForgotPassExpireTime.Minutes()), link),
+	})
+	if err != nil {
+		logr.L.Error("send forgot password mail error.", zap.String("error", err.Error()))
+	}
+
+	return nil
+}
+
+func (s *Service) ResetPassword(req *apireq.ResetPassword) error {
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{ForgotPassToken: helper.Sha256Str(req.Token)})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+	if acc == nil || acc.IsDisable || acc.ForgotPassTokenExpiredAt.Before(time.Now().UTC()) {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "reset token is not valid.", nil)
+		return paramErr
+	}
+
+	pw, err := helper.HashPassword(req.Password)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "hash password error.", err)
+		return unknownErr
+	}
+
+	// Token 只能使用一次
+	acc.Password = pw
+	acc.ForgotPassToken = ""
+	acc.ForgotPassTokenExpiredAt = time.Time{}
+	err = s.sysAccRepo.Update(acc, "password", "forgot_pass_token", "forgot_pass_token_expired_at")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return updateErr
+	}
+
+	// 重設密碼後登出所有裝置，並解除登入失敗的鎖定
+	err = s.tokenService.LogoutAll(acc.Id)
+	if err != nil {
+		return err
+	}
+
+	return s.tokenService.UnlockAccount(acc.Id)
+}
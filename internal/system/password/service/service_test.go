@@ -0,0 +1,90 @@
+package service
+
+import (
+	"net/url"
+	"oauth2-console-go/config"
+	"oauth2-console-go/driver"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/model"
+	loginLogRepo "oauth2-console-go/internal/system/login_log/repository"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	tokenRepo "oauth2-console-go/internal/token/repository"
+	tokenSrv "oauth2-console-go/internal/token/service"
+	"oauth2-console-go/pkg/helper"
+	"oauth2-console-go/pkg/mailer"
+	"oauth2-console-go/pkg/valider"
+	"os"
+	"regexp"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setUp()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func setUp() {
+	config.InitEnv()
+	valider.Init()
+}
+
+func TestService_ForgotPassword(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := tokenSrv.NewService(sar, llr, tc)
+	outbox := mailer.NewOutbox("", "no-reply@example.com")
+	ps := NewService(sar, ts, outbox)
+
+	acc, _ := sar.FindOne(&model.SysAccount{Id: 1})
+	originPassword := acc.Password
+
+	// Act
+	err := ps.ForgotPassword(&apireq.ForgotPassword{Email: acc.Email})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, outbox.Messages(), 1)
+	assert.Equal(t, []string{acc.Email}, outbox.Last().To)
+
+	// 重寄間隔內不再寄送
+	err = ps.ForgotPassword(&apireq.ForgotPassword{Email: acc.Email})
+	assert.Nil(t, err)
+	assert.Len(t, outbox.Messages(), 1)
+
+	// 帳號不存在同樣回傳成功
+	err = ps.ForgotPassword(&apireq.ForgotPassword{Email: "not-exist@example.com"})
+	assert.Nil(t, err)
+	assert.Len(t, outbox.Messages(), 1)
+
+	// 以信件中的 token 重設密碼
+	match := regexp.MustCompile(`token=([^\s]+)`).FindStringSubmatch(outbox.Last().Body)
+	assert.Len(t, match, 2)
+	resetToken, _ := url.QueryUnescape(match[1])
+
+	err = ps.ResetPassword(&apireq.ResetPassword{Token: "invalid-token", Password: "B12345678"})
+	assert.NotNil(t, err)
+
+	err = ps.ResetPassword(&apireq.ResetPassword{Token: resetToken, Password: "B12345678"})
+	assert.Nil(t, err)
+
+	acc, _ = sar.FindOne(&model.SysAccount{Id: 1})
+	ok, _ := helper.VerifyPassword(acc.Password, "B12345678")
+	assert.True(t, ok)
+	assert.Empty(t, acc.ForgotPassToken)
+
+	// Token 只能使用一次
+	err = ps.ResetPassword(&apireq.ResetPassword{Token: resetToken, Password: "C12345678"})
+	assert.NotNil(t, err)
+
+	// Teardown
+	acc.Password = originPassword
+	_ = sar.Update(acc, "password")
+}
@@ -0,0 +1,20 @@
+package library
+
+import "oauth2-console-go/dto/model"
+
+// 角色的權限等級，數字越大權限越高
+var roleLevels = map[string]int{
+	model.RoleViewer: 1,
+	model.RoleEditor: 2,
+	model.RoleAdmin:  3,
+}
+
+// HasRole 判斷角色是否具有 required 以上的權限，未知的角色一律不允許
+func HasRole(role, required string) bool {
+	level, ok := roleLevels[role]
+	if !ok {
+		return false
+	}
+
+	return level >= roleLevels[required]
+}
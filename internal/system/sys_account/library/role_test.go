@@ -0,0 +1,40 @@
+package library
+
+import (
+	"fmt"
+	"oauth2-console-go/dto/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasRole(t *testing.T) {
+	testCases := []struct {
+		Role     string
+		Required string
+		Want     bool
+	}{
+		{model.RoleAdmin, model.RoleAdmin, true},
+		{model.RoleAdmin, model.RoleEditor, true},
+		{model.RoleAdmin, model.RoleViewer, true},
+		{model.RoleEditor, model.RoleAdmin, false},
+		{model.RoleEditor, model.RoleEditor, true},
+		{model.RoleEditor, model.RoleViewer, true},
+		{model.RoleViewer, model.RoleAdmin, false},
+		{model.RoleViewer, model.RoleEditor, false},
+		{model.RoleViewer, model.RoleViewer, true},
+		{"", model.RoleViewer, false},
+		{"owner", model.RoleViewer, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("Role:%s,Required:%s", tc.Role, tc.Required), func(t *testing.T) {
+			// Act
+			ok := HasRole(tc.Role, tc.Required)
+
+			// Assert
+			assert.Equal(t, tc.Want, ok)
+		})
+	}
+}
@@ -7,5 +7,6 @@ type Repository interface {
 	Find(offset, limit int) ([]*model.SysAccount, error)
 	FindOne(m *model.SysAccount) (*model.SysAccount, error)
 	Count() (int, error)
-	Update(m *model.SysAccount) error
+	Update(m *model.SysAccount, cols ...string) error
+	UpdateTotpCounter(accId int, counter int64) (bool, error)
 }
@@ -57,10 +57,28 @@ func (r *Repository) Count() (int, error) {
 	return int(count), nil
 }
 
-func (r *Repository) Update(m *model.SysAccount) error {
-	_, err := r.orm.ID(m.Id).Update(m)
+// Update 預設只更新非零值欄位，需要更新為零值(空字串、false)時指定 cols
+func (r *Repository) Update(m *model.SysAccount, cols ...string) error {
+	session := r.orm.ID(m.Id)
+	if len(cols) > 0 {
+		session = session.Cols(cols...)
+	}
+
+	_, err := session.Update(m)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// UpdateTotpCounter 記錄最後一次通過驗證的 TOTP 時間區間，counter 未大於目前的值時不更新並回傳 false
+func (r *Repository) UpdateTotpCounter(accId int, counter int64) (bool, error) {
+	affected, err := r.orm.Where("id = ? AND totp_last_counter < ?", accId, counter).
+		Cols("totp_last_counter").
+		Update(&model.SysAccount{TotpLastCounter: counter})
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -140,3 +140,31 @@ func TestRepository_Update(t *testing.T) {
 	// Teardown
 	_, _ = orm.ID(acc.Id).Update(&acc)
 }
+
+func TestRepository_UpdateTotpCounter(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := NewRepository(orm)
+
+	acc := model.SysAccount{Id: 1}
+	_, _ = orm.Get(&acc)
+	counter := acc.TotpLastCounter + 1
+
+	// Act
+	ok, err := sar.UpdateTotpCounter(acc.Id, counter)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// Same counter again
+	// Act
+	ok, err = sar.UpdateTotpCounter(acc.Id, counter)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// Teardown
+	_, _ = orm.ID(acc.Id).Cols("totp_last_counter").Update(&acc)
+}
@@ -0,0 +1,17 @@
+package sys_account
+
+import (
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+)
+
+type Service interface {
+	ListAccount(req *apireq.ListSysAccount) (*apires.ListSysAccount, error)
+	GetAccount(sysAccId, accId int) (*apires.SysAccount, error)
+	AddAccount(req *apireq.AddSysAccount) (*apires.SysAccount, error)
+	EditAccount(accId int, req *apireq.EditSysAccount) (bool, error)
+	DisableAccount(sysAccId, accId int) error
+	EnableAccount(sysAccId, accId int) error
+	GetProfile(accId int) (*apires.SysAccount, error)
+	EditProfile(accId int, req *apireq.EditProfile) error
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"net/http"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/apires"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/sys_account"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/helper"
+	"time"
+)
+
+type Service struct {
+	sysAccRepo sys_account.Repository
+}
+
+func NewService(sar sys_account.Repository) sys_account.Service {
+	return &Service{
+		sysAccRepo: sar,
+	}
+}
+
+func (s *Service) ListAccount(req *apireq.ListSysAccount) (*apires.ListSysAccount, error) {
+	err := s.checkAccount(req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.sysAccRepo.Count()
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "count account error.", err)
+		return nil, unknownErr
+	}
+
+	page := req.Page
+	if page <= 1 {
+		page = 1
+	}
+
+	perPage := req.PerPage
+	if perPage <= 1 {
+		perPage = 1
+	}
+
+	offset := (page - 1) * perPage
+
+	accounts, err := s.sysAccRepo.Find(offset, perPage)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, unknownErr
+	}
+
+	list := make([]*apires.SysAccount, 0, len(accounts))
+	for _, acc := range accounts {
+		list = append(list, apires.NewSysAccount(acc))
+	}
+
+	res := apires.ListSysAccount{
+		List:        list,
+		Total:       total,
+		CurrentPage: page,
+		PerPage:     perPage,
+	}
+
+	return &res, nil
+}
+
+func (s *Service) GetAccount(sysAccId, accId int) (*apires.SysAccount, error) {
+	err := s.checkAccount(sysAccId)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := s.findAccount(accId)
+	if err != nil {
+		return nil, err
+	}
+
+	return apires.NewSysAccount(acc), nil
+}
+
+func (s *Service) AddAccount(req *apireq.AddSysAccount) (*apires.SysAccount, error) {
+	err := s.checkAccount(req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check account unique
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Account: req.Account})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc != nil {
+		duplicateErr := er.NewAppErr(http.StatusBadRequest, er.DataDuplicateError, "account duplicate error.", nil)
+		return nil, duplicateErr
+	}
+
+	pw, err := helper.HashPassword(req.Password)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "hash password error.", err)
+		return nil, unknownErr
+	}
+
+	// Insert account
+	m := model.SysAccount{
+		Account:     req.Account,
+		Password:    pw,
+		Name:        req.Name,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Role:        req.Role,
+		MfaRequired: req.MfaRequired,
+	}
+
+	err = s.sysAccRepo.Insert(&m)
+	if err != nil {
+		insertErr := er.NewAppErr(http.StatusInternalServerError, er.DBInsertError, "insert account error.", err)
+		return nil, insertErr
+	}
+
+	return apires.NewSysAccount(&m), nil
+}
+
+// EditAccount 回傳值表示角色或兩步驟驗證要求已變更，須撤銷該帳號已簽發的 token
+func (s *Service) EditAccount(accId int, req *apireq.EditSysAccount) (bool, error) {
+	err := s.checkAccount(req.AccountId)
+	if err != nil {
+		return false, err
+	}
+
+	acc, err := s.findAccount(accId)
+	if err != nil {
+		return false, err
+	}
+
+	// 不可變更自己的角色，避免移除最後一個管理員
+	if req.AccountId == accId && req.Role != acc.Role {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "can not change own role.", nil)
+		return false, paramErr
+	}
+
+	// token 內的角色於簽發時決定，變更後須重新登入
+	revoke := acc.Role != req.Role || acc.MfaRequired != *req.MfaRequired
+
+	// Update account
+	acc.Name = req.Name
+	// email 變更後須重新驗證
+	if acc.Email != req.Email {
+		acc.VerifyAt = time.Time{}
+	}
+	acc.Email = req.Email
+	acc.Phone = req.Phone
+	acc.Role = req.Role
+	acc.MfaRequired = *req.MfaRequired
+
+	err = s.sysAccRepo.Update(acc, "name", "email", "phone", "role", "mfa_required", "verify_at")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return false, updateErr
+	}
+
+	return revoke, nil
+}
+
+func (s *Service) DisableAccount(sysAccId, accId int) error {
+	err := s.checkAccount(sysAccId)
+	if err != nil {
+		return err
+	}
+
+	// 不可停用自己的帳號
+	if sysAccId == accId {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "can not disable own account.", nil)
+		return paramErr
+	}
+
+	acc, err := s.findAccount(accId)
+	if err != nil {
+		return err
+	}
+	if acc.IsDisable {
+		return nil
+	}
+
+	acc.IsDisable = true
+	err = s.sysAccRepo.Update(acc, "is_disable")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return updateErr
+	}
+
+	return nil
+}
+
+func (s *Service) EnableAccount(sysAccId, accId int) error {
+	err := s.checkAccount(sysAccId)
+	if err != nil {
+		return err
+	}
+
+	acc, err := s.findAccount(accId)
+	if err != nil {
+		return err
+	}
+	if !acc.IsDisable {
+		return nil
+	}
+
+	acc.IsDisable = false
+	err = s.sysAccRepo.Update(acc, "is_disable")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return updateErr
+	}
+
+	return nil
+}
+
+func (s *Service) GetProfile(accId int) (*apires.SysAccount, error) {
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc == nil || acc.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return nil, notFoundErr
+	}
+
+	return apires.NewSysAccount(acc), nil
+}
+
+func (s *Service) EditProfile(accId int, req *apireq.EditProfile) error {
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+	if acc == nil || acc.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return notFoundErr
+	}
+
+	// Update profile
+	acc.Name = req.Name
+	// email 變更後須重新驗證
+	if acc.Email != req.Email {
+		acc.VerifyAt = time.Time{}
+	}
+	acc.Email = req.Email
+	acc.Phone = req.Phone
+
+	err = s.sysAccRepo.Update(acc, "name", "email", "phone", "verify_at")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return updateErr
+	}
+
+	return nil
+}
+
+// checkAccount 確認操作的帳號存在且未停用
+func (s *Service) checkAccount(sysAccId int) error {
+	// Check account id exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: sysAccId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+	if acc == nil || acc.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return notFoundErr
+	}
+
+	return nil
+}
+
+// findAccount 取得被操作的帳號，已停用的帳號仍可查詢及編輯
+func (s *Service) findAccount(accId int) (*model.SysAccount, error) {
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc == nil {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return nil, notFoundErr
+	}
+
+	return acc, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"oauth2-console-go/config"
+	"oauth2-console-go/driver"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/model"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/valider"
+	"os"
+	"strconv"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setUp()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func setUp() {
+	config.InitEnv()
+	valider.Init()
+}
+
+func TestService_ListAccount(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := sysAccRepo.NewRepository(orm)
+	sas := NewService(sar)
+
+	testCases := []struct {
+		Page      int
+		PerPage   int
+		WantCount int
+	}{
+		{
+			1,
+			2,
+			1,
+		},
+		{
+			2,
+			10,
+			0,
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("List Sys Account,Page:%d,PerPage:%d", tc.Page, tc.PerPage), func(t *testing.T) {
+			res, err := sas.ListAccount(&apireq.ListSysAccount{AccountId: 1, Page: tc.Page, PerPage: tc.PerPage})
+			assert.Nil(t, err)
+			assert.Len(t, res.List, tc.WantCount)
+			assert.Equal(t, 1, res.Total)
+		})
+	}
+}
+
+func TestService_GetAccount(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := sysAccRepo.NewRepository(orm)
+	sas := NewService(sar)
+
+	// Act
+	res, err := sas.GetAccount(1, 1)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "sys_account", res.Account)
+
+	// No data
+	res, err = sas.GetAccount(1, 999999)
+	assert.Nil(t, res)
+	notFoundErr := err.(*er.AppError)
+	assert.Equal(t, http.StatusBadRequest, notFoundErr.StatusCode)
+	assert.Equal(t, strconv.Itoa(er.ResourceNotFoundError), notFoundErr.Code)
+}
+
+func TestService_AddAccount(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := sysAccRepo.NewRepository(orm)
+	sas := NewService(sar)
+
+	req := apireq.AddSysAccount{
+		AccountId: 1,
+		Account:   "test_account",
+		Password:  "A12345678",
+		Name:      "test_name",
+		Email:     "test@email.com",
+		Phone:     "0912345678",
+		Role:      model.RoleViewer,
+	}
+
+	// Act
+	res, err := sas.AddAccount(&req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, req.Account, res.Account)
+	assert.Equal(t, model.RoleViewer, res.Role)
+
+	acc, _ := sar.FindOne(&model.SysAccount{Id: res.Id})
+	assert.NotEqual(t, req.Password, acc.Password)
+
+	// Duplicate
+	_, err = sas.AddAccount(&req)
+	duplicateErr := err.(*er.AppError)
+	assert.Equal(t, strconv.Itoa(er.DataDuplicateError), duplicateErr.Code)
+
+	// Edit
+	mfaRequired := true
+	revoke, err := sas.EditAccount(res.Id, &apireq.EditSysAccount{
+		AccountId:   1,
+		Name:        "test_name_edit",
+		Email:       "test_edit@email.com",
+		Role:        model.RoleEditor,
+		MfaRequired: &mfaRequired,
+	})
+	assert.Nil(t, err)
+	assert.True(t, revoke)
+	acc, _ = sar.FindOne(&model.SysAccount{Id: res.Id})
+	assert.Equal(t, "test_name_edit", acc.Name)
+	assert.Equal(t, "", acc.Phone)
+	assert.Equal(t, model.RoleEditor, acc.Role)
+	assert.True(t, acc.MfaRequired)
+
+	// 角色未變更不須撤銷 token
+	revoke, err = sas.EditAccount(res.Id, &apireq.EditSysAccount{
+		AccountId:   1,
+		Name:        "test_name_edit_again",
+		Email:       "test_edit@email.com",
+		Role:        model.RoleEditor,
+		MfaRequired: &mfaRequired,
+	})
+	assert.Nil(t, err)
+	assert.False(t, revoke)
+
+	// 不可變更自己的角色
+	self, _ := sar.FindOne(&model.SysAccount{Id: 1})
+	_, err = sas.EditAccount(1, &apireq.EditSysAccount{
+		AccountId:   1,
+		Name:        self.Name,
+		Email:       self.Email,
+		Phone:       self.Phone,
+		Role:        model.RoleViewer,
+		MfaRequired: &self.MfaRequired,
+	})
+	paramErr := err.(*er.AppError)
+	assert.Equal(t, strconv.Itoa(er.ErrorParamInvalid), paramErr.Code)
+
+	// Disable
+	err = sas.DisableAccount(1, res.Id)
+	assert.Nil(t, err)
+	acc, _ = sar.FindOne(&model.SysAccount{Id: res.Id})
+	assert.True(t, acc.IsDisable)
+
+	// Enable
+	err = sas.EnableAccount(1, res.Id)
+	assert.Nil(t, err)
+	acc, _ = sar.FindOne(&model.SysAccount{Id: res.Id})
+	assert.False(t, acc.IsDisable)
+
+	// Teardown
+	_, _ = orm.ID(res.Id).Delete(&model.SysAccount{})
+}
+
+func TestService_DisableAccount(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := sysAccRepo.NewRepository(orm)
+	sas := NewService(sar)
+
+	// Act
+	err := sas.DisableAccount(1, 1)
+
+	// Assert
+	paramErr := err.(*er.AppError)
+	assert.Equal(t, strconv.Itoa(er.ErrorParamInvalid), paramErr.Code)
+}
+
+func TestService_EditProfile(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	sar := sysAccRepo.NewRepository(orm)
+	sas := NewService(sar)
+
+	origin, _ := sas.GetProfile(1)
+	req := apireq.EditProfile{
+		Name:  "test_name",
+		Email: "test@email.com",
+		Phone: "0912345678",
+	}
+
+	// Act
+	err := sas.EditProfile(1, &req)
+
+	// Assert
+	assert.Nil(t, err)
+	res, err := sas.GetProfile(1)
+	assert.Nil(t, err)
+	assert.Equal(t, req.Name, res.Name)
+	assert.Equal(t, req.Email, res.Email)
+	assert.Equal(t, req.Phone, res.Phone)
+
+	// Teardown
+	_ = sas.EditProfile(1, &apireq.EditProfile{
+		Name:  origin.Name,
+		Email: origin.Email,
+		Phone: origin.Phone,
+	})
+}
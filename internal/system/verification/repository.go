@@ -0,0 +1,28 @@
+package verification
+
+import (
+	"fmt"
+	"oauth2-console-go/dto/model"
+	"time"
+)
+
+type Cache interface {
+	GetEmailVerification(tokenHash string) (*model.EmailVerification, error)
+	SetEmailVerification(ev *model.EmailVerification, expiration time.Duration) error
+	DeleteEmailVerification(accId int) error
+	LockResend(accId int, email string, expiration time.Duration) (bool, error)
+}
+
+func GetEmailVerificationRedisKey(tokenHash string) string {
+	return fmt.Sprintf("email_verification:%s", tokenHash)
+}
+
+// 帳號目前有效的驗證 token，重新寄送時撤銷舊的 token
+func GetAccountEmailVerificationRedisKey(accId int) string {
+	return fmt.Sprintf("email_verification:account:%d", accId)
+}
+
+// 重寄間隔依 email 計算，變更 email 後可立即寄送至新的 email
+func GetEmailVerificationResendRedisKey(accId int, email string) string {
+	return fmt.Sprintf("email_verification:resend:%d:%s", accId, email)
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"encoding/json"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/verification"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+type Cache struct {
+	redisCluster *redis.ClusterClient
+}
+
+func NewRedis(rc *redis.ClusterClient) verification.Cache {
+	return &Cache{
+		redisCluster: rc,
+	}
+}
+
+func (c *Cache) GetEmailVerification(tokenHash string) (*model.EmailVerification, error) {
+	key := verification.GetEmailVerificationRedisKey(tokenHash)
+	str, err := c.redisCluster.Get(key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ev := model.EmailVerification{}
+	err = json.Unmarshal([]byte(str), &ev)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ev, nil
+}
+
+func (c *Cache) SetEmailVerification(ev *model.EmailVerification, expiration time.Duration) error {
+	// 撤銷尚未使用的舊 token
+	err := c.DeleteEmailVerification(ev.AccountId)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.Set(verification.GetEmailVerificationRedisKey(ev.TokenHash), string(data), expiration).Err()
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.Set(verification.GetAccountEmailVerificationRedisKey(ev.AccountId), ev.TokenHash, expiration).Err()
+	return err
+}
+
+func (c *Cache) DeleteEmailVerification(accId int) error {
+	accKey := verification.GetAccountEmailVerificationRedisKey(accId)
+	tokenHash, err := c.redisCluster.Get(accKey).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.Del(verification.GetEmailVerificationRedisKey(tokenHash)).Err()
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.Del(accKey).Err()
+	return err
+}
+
+func (c *Cache) LockResend(accId int, email string, expiration time.Duration) (bool, error) {
+	ok, err := c.redisCluster.SetNX(verification.GetEmailVerificationResendRedisKey(accId, email), 1, expiration).Result()
+	return ok, err
+}
@@ -0,0 +1,9 @@
+package verification
+
+import "oauth2-console-go/dto/apireq"
+
+type Service interface {
+	SendVerification(accId int) error
+	ConfirmEmail(req *apireq.ConfirmEmail) error
+	ResendVerification(req *apireq.ResendVerification) error
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/sys_account"
+	"oauth2-console-go/internal/system/verification"
+	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/helper"
+	"oauth2-console-go/pkg/logr"
+	"oauth2-console-go/pkg/mailer"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	sysAccRepo        sys_account.Repository
+	verificationCache verification.Cache
+	mailer            mailer.Mailer
+}
+
+func NewService(sar sys_account.Repository, vc verification.Cache, m mailer.Mailer) verification.Service {
+	return &Service{
+		sysAccRepo:        sar,
+		verificationCache: vc,
+		mailer:            m,
+	}
+}
+
+func (s *Service) SendVerification(accId int) error {
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+	if acc == nil || acc.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return notFoundErr
+	}
+	if !acc.VerifyAt.IsZero() {
+		return nil
+	}
+
+	return s.send(acc)
+}
+
+func (s *Service) ConfirmEmail(req *apireq.ConfirmEmail) error {
+	ev, err := s.verificationCache.GetEmailVerification(helper.Sha256Str(req.Token))
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get email verification error.", err)
+		return redisErr
+	}
+	if ev == nil || ev.ExpiredAt.Before(time.Now().UTC()) {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "verification token is not valid.", nil)
+		return paramErr
+	}
+
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: ev.AccountId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+
+	// 寄出後 email 已變更，須以新的 email 重新驗證
+	if acc == nil || acc.IsDisable || acc.Email != ev.Email {
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "verification token is not valid.", nil)
+		return paramErr
+	}
+
+	if acc.VerifyAt.IsZero() {
+		acc.VerifyAt = time.Now().UTC()
+		err = s.sysAccRepo.Update(acc, "verify_at")
+		if err != nil {
+			updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+			return updateErr
+		}
+	}
+
+	// Token 只能使用一次
+	err = s.verificationCache.DeleteEmailVerification(acc.Id)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete email verification error.", err)
+		return redisErr
+	}
+
+	return nil
+}
+
+func (s *Service) ResendVerification(req *apireq.ResendVerification) error {
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Email: req.Email})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+
+	// 帳號不存在或已驗證時同樣回傳成功，避免被用來探測 email
+	if acc == nil || acc.IsDisable || !acc.VerifyAt.IsZero() {
+		return nil
+	}
+
+	return s.send(acc)
+}
+
+// send 產生新的驗證 token 並寄出，重寄間隔內不再寄送
+func (s *Service) send(acc *model.SysAccount) error {
+	ok, err := s.verificationCache.LockResend(acc.Id, acc.Email, config.EmailVerifyResendTime)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "lock verification resend error.", err)
+		return redisErr
+	}
+	if !ok {
+		return nil
+	}
+
+	verifyToken, err := helper.RandomUrlSafe(32)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "generate verification token error.", err)
+		return unknownErr
+	}
+
+	// Redis 只保存 hash
+	err = s.verificationCache.SetEmailVerification(&model.EmailVerification{
+		TokenHash: helper.Sha256Str(verifyToken),
+		AccountId: acc.Id,
+		Email:     acc.Email,
+		ExpiredAt: time.Now().UTC().Add(config.EmailVerifyExpireTime),
+	}, config.EmailVerifyExpireTime)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "set email verification error.", err)
+		return redisErr
+	}
+
+	if s.mailer == nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "mailer is not configured.", nil)
+		return unknownErr
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", config.GetConsoleUrl(), url.QueryEscape(verifyToken))
+	err = s.mailer.Send(&mailer.Message{
+		To:      []string{acc.Email},
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to verify your email. The link expires in %d hours.\n\n%s\n",
+			acc.Name, int(config.EmailVerifyExpireTime.Hours()), link),
+	})
+	if err != nil {
+		logr.L.Error("send verification mail error.", zap.String("error", err.Error()))
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "send mail error.", err)
+		return unknownErr
+	}
+
+	return nil
+}
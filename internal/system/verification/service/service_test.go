@@ -0,0 +1,116 @@
+package service
+
+import (
+	"net/url"
+	"oauth2-console-go/config"
+	"oauth2-console-go/driver"
+	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/model"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	"oauth2-console-go/internal/system/verification"
+	verificationRepo "oauth2-console-go/internal/system/verification/repository"
+	"oauth2-console-go/pkg/mailer"
+	"oauth2-console-go/pkg/valider"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setUp()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func setUp() {
+	config.InitEnv()
+	valider.Init()
+}
+
+func TestService_ConfirmEmail(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	vc := verificationRepo.NewRedis(rc)
+	outbox := mailer.NewOutbox("", "no-reply@example.com")
+	vs := NewService(sar, vc, outbox)
+
+	accId := 1
+	acc, _ := sar.FindOne(&model.SysAccount{Id: accId})
+	acc.VerifyAt = time.Time{}
+	_ = sar.Update(acc, "verify_at")
+
+	// Act
+	err := vs.SendVerification(accId)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, outbox.Messages(), 1)
+	assert.Equal(t, []string{acc.Email}, outbox.Last().To)
+
+	// 重寄間隔內不再寄送
+	err = vs.ResendVerification(&apireq.ResendVerification{Email: acc.Email})
+	assert.Nil(t, err)
+	assert.Len(t, outbox.Messages(), 1)
+
+	match := regexp.MustCompile(`token=([^\s]+)`).FindStringSubmatch(outbox.Last().Body)
+	assert.Len(t, match, 2)
+	verifyToken, _ := url.QueryUnescape(match[1])
+
+	err = vs.ConfirmEmail(&apireq.ConfirmEmail{Token: "invalid-token"})
+	assert.NotNil(t, err)
+
+	err = vs.ConfirmEmail(&apireq.ConfirmEmail{Token: verifyToken})
+	assert.Nil(t, err)
+
+	acc, _ = sar.FindOne(&model.SysAccount{Id: accId})
+	assert.False(t, acc.VerifyAt.IsZero())
+
+	// Token 只能使用一次
+	err = vs.ConfirmEmail(&apireq.ConfirmEmail{Token: verifyToken})
+	assert.NotNil(t, err)
+
+	// Teardown
+	_ = rc.Del(verification.GetEmailVerificationResendRedisKey(accId, acc.Email)).Err()
+}
+
+func TestService_SendVerification_EmailChanged(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	vc := verificationRepo.NewRedis(rc)
+	outbox := mailer.NewOutbox("", "no-reply@example.com")
+	vs := NewService(sar, vc, outbox)
+
+	accId := 1
+	acc, _ := sar.FindOne(&model.SysAccount{Id: accId})
+	originEmail := acc.Email
+	acc.VerifyAt = time.Time{}
+	_ = sar.Update(acc, "verify_at")
+	_ = vs.SendVerification(accId)
+
+	// 變更 email，重寄間隔不影響新的 email
+	acc.Email = "changed@example.com"
+	_ = sar.Update(acc, "email")
+
+	// Act
+	err := vs.SendVerification(accId)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, outbox.Messages(), 2)
+	assert.Equal(t, []string{"changed@example.com"}, outbox.Last().To)
+
+	// Teardown
+	_ = rc.Del(verification.GetEmailVerificationResendRedisKey(accId, originEmail)).Err()
+	_ = rc.Del(verification.GetEmailVerificationResendRedisKey(accId, acc.Email)).Err()
+	acc.Email = originEmail
+	acc.VerifyAt = time.Now().UTC()
+	_ = sar.Update(acc, "email", "verify_at")
+}
@@ -0,0 +1,225 @@
+package token_library
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/apires"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Key JWT 簽章用的金鑰，kid 取自 PEM 檔名(不含副檔名)
+type Key struct {
+	Id         string
+	Method     jwt.SigningMethod
+	PublicKey  crypto.PublicKey
+	privateKey crypto.PrivateKey
+}
+
+// KeySet 包含一把簽發用的 active key，以及僅用於驗證的 retired keys
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+var keySet *KeySet
+
+// InitKeySet 依設定載入 JWT 金鑰，未設定 active key 時沿用 JWT_SALT 的 HS384 簽章
+func InitKeySet() error {
+	activePath := config.GetJwtSigningKeyPath()
+	if activePath == "" {
+		keySet = nil
+		return nil
+	}
+
+	ks, err := LoadKeySet(activePath, config.GetJwtVerifyingKeyPaths())
+	if err != nil {
+		return err
+	}
+
+	keySet = ks
+	return nil
+}
+
+func LoadKeySet(activePath string, retiredPaths []string) (*KeySet, error) {
+	ks := KeySet{keys: map[string]*Key{}}
+
+	active, err := loadKey(activePath)
+	if err != nil {
+		return nil, err
+	}
+	if active.privateKey == nil {
+		return nil, fmt.Errorf("jwt signing key %s has no private key", activePath)
+	}
+	ks.active = active
+	ks.keys[active.Id] = active
+
+	for _, path := range retiredPaths {
+		key, err := loadKey(path)
+		if err != nil {
+			return nil, err
+		}
+		if ks.keys[key.Id] != nil {
+			return nil, fmt.Errorf("jwt key id %s duplicate", key.Id)
+		}
+
+		// retired key 只用於驗證
+		key.privateKey = nil
+		ks.keys[key.Id] = key
+	}
+
+	return &ks, nil
+}
+
+func (ks *KeySet) Active() *Key {
+	return ks.active
+}
+
+func (ks *KeySet) Get(kid string) *Key {
+	return ks.keys[kid]
+}
+
+func (ks *KeySet) Jwks() *apires.Jwks {
+	jwks := apires.Jwks{Keys: make([]*apires.JwksKey, 0)}
+
+	// active key 排第一個，其餘依 kid 排序為 retired keys
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		if kid != ks.active.Id {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+
+	jwks.Keys = append(jwks.Keys, genJwksKey(ks.active))
+	for _, kid := range kids {
+		jwks.Keys = append(jwks.Keys, genJwksKey(ks.keys[kid]))
+	}
+
+	return &jwks
+}
+
+// GetJwks 取得目前的公開金鑰，未設定非對稱金鑰時回傳空的 key set
+func GetJwks() *apires.Jwks {
+	if keySet == nil {
+		return &apires.Jwks{Keys: make([]*apires.JwksKey, 0)}
+	}
+
+	return keySet.Jwks()
+}
+
+func loadKey(path string) (*Key, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(config.GetBasePath(), path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt key %s must be PEM encoded", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			parsed = cert.PublicKey
+		}
+	default:
+		err = fmt.Errorf("unsupported PEM block type %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := Key{
+		Id: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.privateKey = k
+		key.PublicKey = &k.PublicKey
+	case *ecdsa.PrivateKey:
+		key.privateKey = k
+		key.PublicKey = &k.PublicKey
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		key.PublicKey = k
+	default:
+		return nil, fmt.Errorf("jwt key %s type not supported", path)
+	}
+
+	switch pub := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return nil, errors.New("only P-256 curve is supported for ES256")
+		}
+		key.Method = jwt.SigningMethodES256
+	}
+
+	return &key, nil
+}
+
+func genJwksKey(key *Key) *apires.JwksKey {
+	jk := apires.JwksKey{
+		Kid: key.Id,
+		Use: "sig",
+		Alg: key.Method.Alg(),
+	}
+
+	switch pub := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jk.Kty = "RSA"
+		jk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jk.Kty = "EC"
+		jk.Crv = pub.Curve.Params().Name
+		jk.X = base64.RawURLEncoding.EncodeToString(padBytes(pub.X.Bytes(), size))
+		jk.Y = base64.RawURLEncoding.EncodeToString(padBytes(pub.Y.Bytes(), size))
+	}
+
+	return &jk
+}
+
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
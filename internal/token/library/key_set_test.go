@@ -0,0 +1,132 @@
+package token_library
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/valider"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setUp()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func setUp() {
+	config.InitEnv()
+	valider.Init()
+}
+
+func writeRsaKey(t *testing.T, dir, name string) string {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	path := filepath.Join(dir, name+".pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	err := ioutil.WriteFile(path, data, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writeEcKey(t *testing.T, dir, name string) string {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	path := filepath.Join(dir, name+".pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	err := ioutil.WriteFile(path, data, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadKeySet(t *testing.T) {
+	// Arrange
+	dir, _ := ioutil.TempDir("", "jwt_keys")
+	defer os.RemoveAll(dir)
+
+	activePath := writeEcKey(t, dir, "key-2")
+	retiredPath := writeRsaKey(t, dir, "key-1")
+
+	// Act
+	ks, err := LoadKeySet(activePath, []string{retiredPath})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "key-2", ks.Active().Id)
+	assert.Equal(t, jwt.SigningMethodES256, ks.Active().Method)
+	assert.Equal(t, jwt.SigningMethodRS256, ks.Get("key-1").Method)
+
+	jwks := ks.Jwks()
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "EC", jwks.Keys[0].Kty)
+	assert.Equal(t, "P-256", jwks.Keys[0].Crv)
+	assert.Equal(t, "RSA", jwks.Keys[1].Kty)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+
+	// Duplicate key id
+	// Act
+	_, err = LoadKeySet(activePath, []string{activePath})
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+func TestGenToken(t *testing.T) {
+	// Arrange
+	dir, _ := ioutil.TempDir("", "jwt_keys")
+	defer os.RemoveAll(dir)
+	defer func() { keySet = nil }()
+
+	// Legacy HS384 token
+	keySet = nil
+	legacyToken, _, err := GenToken(1, model.RoleAdmin, "test_session")
+	assert.Nil(t, err)
+
+	// Sign with retired key
+	oldKs, _ := LoadKeySet(writeRsaKey(t, dir, "key-1"), nil)
+	keySet = oldKs
+	oldToken, _, err := GenToken(1, model.RoleAdmin, "test_session")
+	assert.Nil(t, err)
+
+	// Rotate, key-1 becomes retired
+	ks, _ := LoadKeySet(writeEcKey(t, dir, "key-2"), []string{filepath.Join(dir, "key-1.pem")})
+	keySet = ks
+
+	// Act
+	newToken, _, err := GenToken(1, model.RoleAdmin, "test_session")
+
+	// Assert
+	assert.Nil(t, err)
+	for _, tokenStr := range []string{legacyToken, oldToken, newToken} {
+		claims, err := ParseToken(tokenStr)
+		assert.Nil(t, err)
+		assert.Equal(t, "1", claims["account_id"])
+	}
+
+	token, _ := jwt.Parse(newToken, nil)
+	assert.Equal(t, "key-2", token.Header["kid"])
+	assert.Equal(t, "ES256", token.Header["alg"])
+
+	// Unknown key id
+	keySet, _ = LoadKeySet(filepath.Join(dir, "key-2.pem"), nil)
+
+	// Act
+	_, err = ParseToken(oldToken)
+
+	// Assert
+	assert.NotNil(t, err)
+}
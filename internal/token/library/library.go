@@ -1,11 +1,16 @@
 package token_library
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"net/http"
 	"oauth2-console-go/config"
+	"oauth2-console-go/dto/model"
 	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/helper"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -13,30 +18,54 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func parseToken(tokenStr, salt string) (jwt.MapClaims, error) {
+// parseToken 驗證 token 並回傳 claims，legacy 表示為未帶 kid 的舊版 HS384 token
+func parseToken(tokenStr, salt string) (claims jwt.MapClaims, legacy bool, err error) {
 	// Parse takes the token string and a function for looking up the key. The latter is especially
 	// useful if you use multiple keys for your application.  The standard is to use 'kid' in the
 	// head of the token to identify which key to use, but the parsed token (head and claims) is provided
 	// to the callback, providing flexibility.
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		// Don't forget to validate the alg is what you expect:
+		legacy = false
+		// 非對稱金鑰簽發的 token，依 kid 取得驗證用的公鑰
+		if kid, ok := token.Header["kid"].(string); ok {
+			if keySet == nil {
+				return nil, fmt.Errorf("unexpected key id")
+			}
+			key := keySet.Get(kid)
+			if key == nil {
+				return nil, fmt.Errorf("unexpected key id")
+			}
+
+			// Don't forget to validate the alg is what you expect:
+			if token.Method.Alg() != key.Method.Alg() {
+				return nil, fmt.Errorf("unexpected signing method")
+			}
+
+			return key.PublicKey, nil
+		}
+
+		// 舊版 HS384 token，遷移期間只要仍設定 JWT_SALT 就接受
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method")
 		}
+		if salt == "" {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
 
 		// hmacSampleSecret is a []byte containing your secret, e.g. []byte("my_secret_key")
+		legacy = true
 		return []byte(salt), nil
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
+		return claims, legacy, nil
 	}
 
-	return nil, err
+	return nil, false, err
 }
 
 func ParseClaims(claims interface{}) jwt.MapClaims {
@@ -49,21 +78,50 @@ func ParseClaims(claims interface{}) jwt.MapClaims {
 
 // ---------------------------------------- JWT Token Generation ----------------------------------------------
 
-func GenToken(accId int) (string, time.Time, error) {
-	salt := config.GetJwtSalt()
-	secret := []byte(salt)
+func GenToken(accId int, role, sessionId string) (string, time.Time, error) {
+	return genToken(accId, role, sessionId, false)
+}
+
+// GenMfaEnrollToken 產生僅能設定兩步驟驗證的 token，帳號要求兩步驟驗證但尚未啟用時使用
+func GenMfaEnrollToken(accId int, role, sessionId string) (string, time.Time, error) {
+	return genToken(accId, role, sessionId, true)
+}
 
+func genToken(accId int, role, sessionId string, mfaEnroll bool) (string, time.Time, error) {
 	// Create a new token object, specifying signing method and the claims
 	// you would like it to contain.
-	exp := time.Now().Add(time.Hour * 24).UTC()
+	exp := time.Now().Add(config.GetJwtLifetime()).UTC()
 
 	accIdStr := strconv.Itoa(accId)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS384, jwt.MapClaims{
-		"iss":        "address-book-go",
+	claims := jwt.MapClaims{
+		"iss":        config.GetJwtIssuer(),
 		"exp":        exp.Unix(),              // Expiration Time,
 		"iat":        time.Now().UTC().Unix(), // Issued At Time
+		"jti":        sessionId,               // Session Id
 		"account_id": accIdStr,
-	})
+		"role":       role,
+	}
+	if aud := config.GetJwtAudience(); aud != "" {
+		claims["aud"] = aud
+	}
+	if mfaEnroll {
+		claims["mfa_enroll"] = true
+	}
+
+	// 有設定非對稱金鑰時，以 active key 簽章並在 header 帶入 kid
+	if keySet != nil {
+		key := keySet.Active()
+		token := jwt.NewWithClaims(key.Method, claims)
+		token.Header["kid"] = key.Id
+
+		tokenString, err := token.SignedString(key.privateKey)
+
+		return tokenString, exp, err
+	}
+
+	salt := config.GetJwtSalt()
+	secret := []byte(salt)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS384, claims)
 
 	// Sign and get the complete encoded token as a string using the secret
 	tokenString, err := token.SignedString(secret)
@@ -71,11 +129,88 @@ func GenToken(accId int) (string, time.Time, error) {
 	return tokenString, exp, err
 }
 
+// ---------------------------------------- Refresh Token ----------------------------------------------
+
+// GenSessionId 產生 session id，同時作為 refresh token 的 family id，同一次登入輪替出的 refresh token 共用同一個 family
+func GenSessionId() (string, error) {
+	return helper.RandomHex(16)
+}
+
+// GenRefreshToken 產生 refresh token，格式為 {account_id}.{family_id}.{secret}
+func GenRefreshToken(accId int, familyId string) (string, error) {
+	secret, err := helper.RandomUrlSafe(32)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d.%s.%s", accId, familyId, secret), nil
+}
+
+func ParseRefreshToken(refreshToken string) (int, string, error) {
+	arr := strings.Split(refreshToken, ".")
+	if len(arr) != 3 || arr[1] == "" || arr[2] == "" {
+		return 0, "", errors.New("refresh token format error")
+	}
+
+	accId, err := strconv.Atoi(arr[0])
+	if err != nil {
+		return 0, "", err
+	}
+
+	return accId, arr[1], nil
+}
+
+func HashRefreshToken(refreshToken string) string {
+	return helper.Sha256Str(refreshToken)
+}
+
+func CheckRefreshToken(rt *model.RefreshToken, refreshToken string) bool {
+	hash := HashRefreshToken(refreshToken)
+	return subtle.ConstantTimeCompare([]byte(rt.TokenHash), []byte(hash)) == 1
+}
+
 // ---------------------------------------- Middleware JWT Token 驗證 ----------------------------------------------
 
+// HashToken 取得 token 的 hash，用於 redis 撤銷名單的 key
+func HashToken(tokenStr string) string {
+	return helper.Sha256Str(tokenStr)
+}
+
 func ParseToken(tokenStr string) (jwt.MapClaims, error) {
 	salt := config.GetJwtSalt()
-	return parseToken(tokenStr, salt)
+	claims, legacy, err := parseToken(tokenStr, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	// 拒絕其他環境簽發的 token，須明確開啟 JWT_ACCEPT_LEGACY_ISSUER 才接受未帶 kid 的舊版 token 原本的 iss
+	acceptLegacy := legacy && config.GetJwtAcceptLegacyIssuer()
+	if !claims.VerifyIssuer(config.GetJwtIssuer(), true) &&
+		!(acceptLegacy && claims.VerifyIssuer(config.JwtLegacyIssuer, true)) {
+		return nil, errors.New("unexpected issuer")
+	}
+	if aud := config.GetJwtAudience(); aud != "" && !claims.VerifyAudience(aud, true) {
+		return nil, errors.New("unexpected audience")
+	}
+
+	return claims, nil
+}
+
+// mfaEnrollPaths mfa enroll token 可存取的路由，設定完成後需重新登入取得一般 token
+var mfaEnrollPaths = map[string]bool{
+	"/v1/mfa/totp":          true,
+	"/v1/mfa/totp/activate": true,
+	"/v1/token/logout":      true,
+}
+
+// IsMfaEnrollToken 是否為僅能設定兩步驟驗證的 token
+func IsMfaEnrollToken(claims jwt.MapClaims) bool {
+	enroll, _ := claims["mfa_enroll"].(bool)
+	return enroll
+}
+
+func IsMfaEnrollPathAllowed(path string) bool {
+	return mfaEnrollPaths[path]
 }
 
 // ---------------------------------------- JWT Account Id 驗證 ----------------------------------------------
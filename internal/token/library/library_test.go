@@ -0,0 +1,97 @@
+package token_library
+
+import (
+	"oauth2-console-go/dto/model"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseToken(t *testing.T) {
+	// Arrange
+	defer os.Unsetenv("JWT_ISSUER")
+	defer os.Unsetenv("JWT_AUDIENCE")
+
+	_ = os.Setenv("JWT_ISSUER", "console-staging")
+	_ = os.Setenv("JWT_AUDIENCE", "console-api")
+	tokenStr, _, _ := GenToken(1, model.RoleAdmin, "test_session")
+
+	// Act
+	claims, err := ParseToken(tokenStr)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "console-staging", claims["iss"])
+	assert.Equal(t, "console-api", claims["aud"])
+
+	// Token from another deployment
+	testCases := []struct {
+		Name     string
+		Issuer   string
+		Audience string
+	}{
+		{
+			"issuer not matched",
+			"console-production",
+			"console-api",
+		},
+		{
+			"audience not matched",
+			"console-staging",
+			"another-api",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			_ = os.Setenv("JWT_ISSUER", tc.Issuer)
+			_ = os.Setenv("JWT_AUDIENCE", tc.Audience)
+
+			// Act
+			_, err := ParseToken(tokenStr)
+
+			// Assert
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestParseToken_LegacyIssuer(t *testing.T) {
+	// Arrange
+	defer os.Unsetenv("JWT_ISSUER")
+	defer os.Unsetenv("JWT_ACCEPT_LEGACY_ISSUER")
+
+	_ = os.Unsetenv("JWT_ISSUER")
+	tokenStr, _, _ := GenToken(1, model.RoleAdmin, "test_session")
+
+	// Act
+	_ = os.Setenv("JWT_ISSUER", "console-staging")
+	_, rejectErr := ParseToken(tokenStr)
+
+	_ = os.Setenv("JWT_ACCEPT_LEGACY_ISSUER", "true")
+	claims, err := ParseToken(tokenStr)
+
+	// Assert，預設拒絕其他環境的舊版 token，開啟後才接受
+	assert.NotNil(t, rejectErr)
+	assert.Nil(t, err)
+	assert.Equal(t, "address-book-go", claims["iss"])
+}
+
+func TestGenMfaEnrollToken(t *testing.T) {
+	// Arrange
+	tokenStr, _, _ := GenMfaEnrollToken(1, model.RoleAdmin, "test_session")
+	normalStr, _, _ := GenToken(1, model.RoleAdmin, "test_session")
+
+	// Act
+	claims, err := ParseToken(tokenStr)
+	normalClaims, _ := ParseToken(normalStr)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, IsMfaEnrollToken(claims))
+	assert.False(t, IsMfaEnrollToken(normalClaims))
+	assert.True(t, IsMfaEnrollPathAllowed("/v1/mfa/totp/activate"))
+	assert.False(t, IsMfaEnrollPathAllowed("/v1/accounts/1"))
+}
@@ -0,0 +1,38 @@
+package token_library
+
+import (
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/model"
+	"time"
+)
+
+// LoginDelay 依失敗次數計算下次可嘗試登入前需等待的時間，超過門檻後每次加倍
+func LoginDelay(count int) time.Duration {
+	if count < config.LoginDelayThreshold {
+		return 0
+	}
+
+	delay := time.Second
+	for i := config.LoginDelayThreshold; i < count; i++ {
+		delay *= 2
+		if delay >= config.LoginMaxDelay {
+			return config.LoginMaxDelay
+		}
+	}
+
+	return delay
+}
+
+// LoginRetryAfter 距離下次可嘗試登入的剩餘時間
+func LoginRetryAfter(lf *model.LoginFailure, now time.Time) time.Duration {
+	if lf == nil {
+		return 0
+	}
+
+	retryAfter := lf.LastFailedAt.Add(LoginDelay(lf.Count)).Sub(now)
+	if retryAfter < 0 {
+		return 0
+	}
+
+	return retryAfter
+}
@@ -0,0 +1,33 @@
+package token_library
+
+import (
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginDelay(t *testing.T) {
+	assert.Equal(t, time.Duration(0), LoginDelay(0))
+	assert.Equal(t, time.Duration(0), LoginDelay(config.LoginDelayThreshold-1))
+	assert.Equal(t, time.Second, LoginDelay(config.LoginDelayThreshold))
+	assert.Equal(t, time.Second*2, LoginDelay(config.LoginDelayThreshold+1))
+	assert.Equal(t, time.Second*4, LoginDelay(config.LoginDelayThreshold+2))
+	assert.Equal(t, config.LoginMaxDelay, LoginDelay(config.LoginDelayThreshold+20))
+}
+
+func TestLoginRetryAfter(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	lf := model.LoginFailure{
+		Count:        config.LoginDelayThreshold + 2,
+		LastFailedAt: now.Add(-time.Second),
+	}
+
+	// Act & Assert
+	assert.Equal(t, time.Duration(0), LoginRetryAfter(nil, now))
+	assert.Equal(t, time.Second*3, LoginRetryAfter(&lf, now))
+	assert.Equal(t, time.Duration(0), LoginRetryAfter(&lf, now.Add(time.Minute)))
+}
@@ -1,12 +1,99 @@
 package token
 
-import "fmt"
+import (
+	"fmt"
+	"oauth2-console-go/dto/model"
+	"strings"
+	"time"
+)
 
 type Cache interface {
 	GetTokenIat(accId int) (float64, error)
 	SetTokenIat(accId int, iat int64) error
+	GetRefreshToken(accId int, familyId string) (*model.RefreshToken, error)
+	SetRefreshToken(accId int, rt *model.RefreshToken) error
+	SwapRefreshToken(accId int, oldHash string, rt *model.RefreshToken) (bool, error)
+	DeleteRefreshToken(accId int, familyId string) error
+	DeleteAllRefreshToken(accId int) error
+	RevokeToken(accId int, tokenHash string, expiration time.Duration) error
+	IsTokenRevoked(accId int, tokenHash string) (bool, error)
+	FindSession(accId int) ([]*model.Session, error)
+	GetSession(accId int, sessionId string) (*model.Session, error)
+	SetSession(accId int, session *model.Session) error
+	TouchSession(accId int, sessionId string, lastSeenAt time.Time) error
+	DeleteSession(accId int, sessionId string) error
+	DeleteAllSession(accId int) error
+	GetMfaChallenge(challengeId string) (*model.MfaChallenge, error)
+	SetMfaChallenge(challenge *model.MfaChallenge, expiration time.Duration) error
+	IncrMfaChallengeAttempt(challengeId string, expiration time.Duration) (int64, error)
+	DeleteMfaChallenge(challengeId string) error
+	GetLoginFailure(key string) (*model.LoginFailure, error)
+	IncrLoginFailure(key string, failedAt time.Time, expiration time.Duration) (*model.LoginFailure, error)
+	DeleteLoginFailure(key string) error
+	LockAccount(account string, expiration time.Duration) error
+	GetAccountLockTTL(account string) (time.Duration, error)
+	UnlockAccount(account string) error
+	SetAccountDisabled(accId int, isDisable bool) error
+	GetAccountDisabled(accId int) (isDisable bool, found bool, err error)
+	DeleteAccountDisabled(accId int) error
 }
 
+const (
+	refreshTokenFieldPrefix = "refresh:"
+	sessionFieldPrefix      = "session:"
+	sessionSeenFieldPrefix  = "session_seen:"
+)
+
 func GetSysAccountTokenRedisKey(accId int) string {
 	return fmt.Sprintf("sys_account:%v:token", accId)
 }
+
+// 帳號是否停用的快取，由 TokenAuth 統一拒絕已停用的帳號，未快取時以資料庫為準
+func GetAccountDisabledRedisKey(accId int) string {
+	return fmt.Sprintf("sys_account:%v:disabled", accId)
+}
+
+func GetRevokedTokenRedisKey(accId int, tokenHash string) string {
+	return fmt.Sprintf("sys_account:%v:revoked_token:%s", accId, tokenHash)
+}
+
+func GetMfaChallengeRedisKey(challengeId string) string {
+	return fmt.Sprintf("mfa_challenge:%s", challengeId)
+}
+
+func GetMfaChallengeAttemptRedisKey(challengeId string) string {
+	return fmt.Sprintf("mfa_challenge:%s:attempt", challengeId)
+}
+
+// 帳號名稱統一轉為小寫，不存在的帳號同樣累計失敗次數，避免藉由回應差異探測帳號
+func GetAccountLoginFailureRedisKey(account string) string {
+	return fmt.Sprintf("login_failure:account:%s", strings.ToLower(account))
+}
+
+func GetIpLoginFailureRedisKey(ip string) string {
+	return fmt.Sprintf("login_failure:ip:%s", ip)
+}
+
+func GetAccountLockRedisKey(account string) string {
+	return fmt.Sprintf("login_lock:account:%s", strings.ToLower(account))
+}
+
+func GetRefreshTokenField(familyId string) string {
+	return refreshTokenFieldPrefix + familyId
+}
+
+func IsRefreshTokenField(field string) bool {
+	return strings.HasPrefix(field, refreshTokenFieldPrefix)
+}
+
+func GetSessionField(sessionId string) string {
+	return sessionFieldPrefix + sessionId
+}
+
+func GetSessionSeenField(sessionId string) string {
+	return sessionSeenFieldPrefix + sessionId
+}
+
+func IsSessionField(field string) bool {
+	return strings.HasPrefix(field, sessionFieldPrefix)
+}
@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"encoding/json"
+	"oauth2-console-go/config"
+	"oauth2-console-go/dto/model"
 	"oauth2-console-go/internal/token"
 	"strconv"
+	"time"
 
 	"github.com/go-redis/redis/v7"
 )
@@ -17,9 +21,13 @@ func NewRedis(rc *redis.ClusterClient) token.Cache {
 	}
 }
 
+// GetTokenIat 尚未設定時回傳 0
 func (c *Cache) GetTokenIat(accId int) (float64, error) {
 	key := token.GetSysAccountTokenRedisKey(accId)
 	iatStr, err := c.redisCluster.HGet(key, "iat").Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -34,3 +42,408 @@ func (c *Cache) SetTokenIat(accId int, iat int64) error {
 	err := c.redisCluster.HSet(key, "iat", iat).Err()
 	return err
 }
+
+func (c *Cache) GetRefreshToken(accId int, familyId string) (*model.RefreshToken, error) {
+	key := token.GetSysAccountTokenRedisKey(accId)
+	str, err := c.redisCluster.HGet(key, token.GetRefreshTokenField(familyId)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rt := model.RefreshToken{}
+	err = json.Unmarshal([]byte(str), &rt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rt, nil
+}
+
+func (c *Cache) SetRefreshToken(accId int, rt *model.RefreshToken) error {
+	key := token.GetSysAccountTokenRedisKey(accId)
+
+	// 清除已過期的 refresh token family，避免 hash 無限增長
+	fields, err := c.redisCluster.HGetAll(key).Result()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for field, str := range fields {
+		if !token.IsRefreshTokenField(field) {
+			continue
+		}
+		old := model.RefreshToken{}
+		if json.Unmarshal([]byte(str), &old) != nil || old.ExpiredAt.Before(now) {
+			_ = c.redisCluster.HDel(key, field).Err()
+		}
+	}
+
+	data, err := json.Marshal(rt)
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.HSet(key, token.GetRefreshTokenField(rt.FamilyId), string(data)).Err()
+	return err
+}
+
+// swapRefreshTokenScript 僅在目前的 token hash 與舊值相同時才寫入新值，避免同一個 refresh token 被並行使用時分岔出兩組 token
+var swapRefreshTokenScript = redis.NewScript(`
+local cur = redis.call("HGET", KEYS[1], ARGV[1])
+if not cur then
+	return 0
+end
+local ok, rt = pcall(cjson.decode, cur)
+if not ok or rt["token_hash"] ~= ARGV[2] then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+return 1
+`)
+
+func (c *Cache) SwapRefreshToken(accId int, oldHash string, rt *model.RefreshToken) (bool, error) {
+	key := token.GetSysAccountTokenRedisKey(accId)
+
+	data, err := json.Marshal(rt)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := swapRefreshTokenScript.Run(c.redisCluster, []string{key}, token.GetRefreshTokenField(rt.FamilyId), oldHash, string(data)).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return res == 1, nil
+}
+
+func (c *Cache) DeleteRefreshToken(accId int, familyId string) error {
+	key := token.GetSysAccountTokenRedisKey(accId)
+	err := c.redisCluster.HDel(key, token.GetRefreshTokenField(familyId)).Err()
+	return err
+}
+
+func (c *Cache) DeleteAllRefreshToken(accId int) error {
+	key := token.GetSysAccountTokenRedisKey(accId)
+	fields, err := c.redisCluster.HKeys(key).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		if !token.IsRefreshTokenField(field) {
+			continue
+		}
+		err = c.redisCluster.HDel(key, field).Err()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Cache) RevokeToken(accId int, tokenHash string, expiration time.Duration) error {
+	key := token.GetRevokedTokenRedisKey(accId, tokenHash)
+	err := c.redisCluster.Set(key, 1, expiration).Err()
+	return err
+}
+
+func (c *Cache) IsTokenRevoked(accId int, tokenHash string) (bool, error) {
+	key := token.GetRevokedTokenRedisKey(accId, tokenHash)
+	cnt, err := c.redisCluster.Exists(key).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return cnt > 0, nil
+}
+
+func (c *Cache) FindSession(accId int) ([]*model.Session, error) {
+	key := token.GetSysAccountTokenRedisKey(accId)
+	fields, err := c.redisCluster.HGetAll(key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*model.Session, 0)
+	for field, str := range fields {
+		if !token.IsSessionField(field) {
+			continue
+		}
+
+		session := model.Session{}
+		err = json.Unmarshal([]byte(str), &session)
+		if err != nil {
+			return nil, err
+		}
+		setLastSeenAt(&session, fields[token.GetSessionSeenField(session.Id)])
+
+		sessions = append(sessions, &session)
+	}
+
+	return sessions, nil
+}
+
+func (c *Cache) GetSession(accId int, sessionId string) (*model.Session, error) {
+	key := token.GetSysAccountTokenRedisKey(accId)
+	values, err := c.redisCluster.HMGet(key, token.GetSessionField(sessionId), token.GetSessionSeenField(sessionId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	str, ok := values[0].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	session := model.Session{}
+	err = json.Unmarshal([]byte(str), &session)
+	if err != nil {
+		return nil, err
+	}
+
+	seen, _ := values[1].(string)
+	setLastSeenAt(&session, seen)
+
+	return &session, nil
+}
+
+func (c *Cache) SetSession(accId int, session *model.Session) error {
+	key := token.GetSysAccountTokenRedisKey(accId)
+
+	// 清除已過期的 session，避免 hash 無限增長
+	sessions, err := c.FindSession(accId)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, old := range sessions {
+		if old.ExpiredAt.Before(now) {
+			_ = c.DeleteSession(accId, old.Id)
+		}
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.HSet(key, token.GetSessionField(session.Id), string(data)).Err()
+	return err
+}
+
+// TouchSession 更新 session 最後使用時間
+// 最後使用時間另存一個欄位，避免與撤銷 session 同時發生時把已刪除的 session 寫回
+func (c *Cache) TouchSession(accId int, sessionId string, lastSeenAt time.Time) error {
+	key := token.GetSysAccountTokenRedisKey(accId)
+	err := c.redisCluster.HSet(key, token.GetSessionSeenField(sessionId), lastSeenAt.Unix()).Err()
+	return err
+}
+
+func (c *Cache) DeleteSession(accId int, sessionId string) error {
+	key := token.GetSysAccountTokenRedisKey(accId)
+	err := c.redisCluster.HDel(
+		key,
+		token.GetSessionField(sessionId),
+		token.GetSessionSeenField(sessionId),
+		token.GetRefreshTokenField(sessionId),
+	).Err()
+	return err
+}
+
+func (c *Cache) DeleteAllSession(accId int) error {
+	sessions, err := c.FindSession(accId)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		err = c.DeleteSession(accId, session.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setLastSeenAt(session *model.Session, seen string) {
+	ts, err := strconv.ParseInt(seen, 10, 64)
+	if err != nil {
+		return
+	}
+
+	lastSeenAt := time.Unix(ts, 0).UTC()
+	if lastSeenAt.After(session.LastSeenAt) {
+		session.LastSeenAt = lastSeenAt
+	}
+}
+
+func (c *Cache) GetMfaChallenge(challengeId string) (*model.MfaChallenge, error) {
+	key := token.GetMfaChallengeRedisKey(challengeId)
+	str, err := c.redisCluster.Get(key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := model.MfaChallenge{}
+	err = json.Unmarshal([]byte(str), &challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+func (c *Cache) SetMfaChallenge(challenge *model.MfaChallenge, expiration time.Duration) error {
+	key := token.GetMfaChallengeRedisKey(challenge.Id)
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.Set(key, string(data), expiration).Err()
+	return err
+}
+
+func (c *Cache) IncrMfaChallengeAttempt(challengeId string, expiration time.Duration) (int64, error) {
+	key := token.GetMfaChallengeAttemptRedisKey(challengeId)
+	cnt, err := c.redisCluster.Incr(key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// 第一次嘗試時設定期限，與 challenge 一同過期
+	if cnt == 1 {
+		err = c.redisCluster.Expire(key, expiration).Err()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return cnt, nil
+}
+
+func (c *Cache) DeleteMfaChallenge(challengeId string) error {
+	err := c.redisCluster.Del(token.GetMfaChallengeRedisKey(challengeId)).Err()
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.Del(token.GetMfaChallengeAttemptRedisKey(challengeId)).Err()
+	return err
+}
+
+func (c *Cache) GetLoginFailure(key string) (*model.LoginFailure, error) {
+	fields, err := c.redisCluster.HGetAll(key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	lf := model.LoginFailure{}
+	lf.Count, _ = strconv.Atoi(fields["count"])
+	lastFailedAt, _ := strconv.ParseInt(fields["last_failed_at"], 10, 64)
+	lf.LastFailedAt = time.Unix(lastFailedAt, 0).UTC()
+
+	return &lf, nil
+}
+
+func (c *Cache) IncrLoginFailure(key string, failedAt time.Time, expiration time.Duration) (*model.LoginFailure, error) {
+	cnt, err := c.redisCluster.HIncrBy(key, "count", 1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.redisCluster.HSet(key, "last_failed_at", failedAt.Unix()).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// 每次失敗重新計算累計期間
+	err = c.redisCluster.Expire(key, expiration).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	lf := model.LoginFailure{
+		Count:        int(cnt),
+		LastFailedAt: time.Unix(failedAt.Unix(), 0).UTC(),
+	}
+
+	return &lf, nil
+}
+
+func (c *Cache) DeleteLoginFailure(key string) error {
+	err := c.redisCluster.Del(key).Err()
+	return err
+}
+
+func (c *Cache) LockAccount(account string, expiration time.Duration) error {
+	key := token.GetAccountLockRedisKey(account)
+	err := c.redisCluster.Set(key, 1, expiration).Err()
+	return err
+}
+
+func (c *Cache) GetAccountLockTTL(account string) (time.Duration, error) {
+	key := token.GetAccountLockRedisKey(account)
+	ttl, err := c.redisCluster.TTL(key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// key 不存在時 ttl 為負值
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (c *Cache) UnlockAccount(account string) error {
+	err := c.redisCluster.Del(token.GetAccountLockRedisKey(account)).Err()
+	if err != nil {
+		return err
+	}
+
+	err = c.redisCluster.Del(token.GetAccountLoginFailureRedisKey(account)).Err()
+	return err
+}
+
+func (c *Cache) SetAccountDisabled(accId int, isDisable bool) error {
+	key := token.GetAccountDisabledRedisKey(accId)
+	val, expiration := 0, config.AccountEnableCacheTime
+	if isDisable {
+		val, expiration = 1, config.RedisDefaultExpireTime
+	}
+
+	err := c.redisCluster.Set(key, val, expiration).Err()
+	return err
+}
+
+// GetAccountDisabled found 為 false 表示尚未快取
+func (c *Cache) GetAccountDisabled(accId int) (isDisable bool, found bool, err error) {
+	str, err := c.redisCluster.Get(token.GetAccountDisabledRedisKey(accId)).Result()
+	if err == redis.Nil {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+
+	return str == "1", true, nil
+}
+
+func (c *Cache) DeleteAccountDisabled(accId int) error {
+	err := c.redisCluster.Del(token.GetAccountDisabledRedisKey(accId)).Err()
+	return err
+}
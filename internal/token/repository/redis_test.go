@@ -3,6 +3,8 @@ package repository
 import (
 	"oauth2-console-go/config"
 	"oauth2-console-go/driver"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/token"
 	"oauth2-console-go/pkg/valider"
 	"os"
 	"testing"
@@ -60,3 +62,304 @@ func TestCache_SetTokenIat(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestCache_SetRefreshToken(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	accId := 1
+	rt := model.RefreshToken{
+		FamilyId:  "test_family",
+		TokenHash: "test_hash",
+		ExpiredAt: time.Now().Add(time.Hour).UTC(),
+	}
+
+	// Act
+	err := tc.SetRefreshToken(accId, &rt)
+
+	// Assert
+	assert.Nil(t, err)
+
+	// Teardown
+	_ = tc.DeleteRefreshToken(accId, rt.FamilyId)
+}
+
+func TestCache_GetRefreshToken(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	accId := 1
+	rt := model.RefreshToken{
+		FamilyId:  "test_family",
+		TokenHash: "test_hash",
+		ExpiredAt: time.Now().Add(time.Hour).UTC(),
+	}
+	_ = tc.SetRefreshToken(accId, &rt)
+
+	// Act
+	res, err := tc.GetRefreshToken(accId, rt.FamilyId)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotNil(t, res)
+	assert.Equal(t, rt.TokenHash, res.TokenHash)
+
+	// No data
+	_ = tc.DeleteRefreshToken(accId, rt.FamilyId)
+
+	// Act
+	res, err = tc.GetRefreshToken(accId, rt.FamilyId)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, res)
+}
+
+func TestCache_SwapRefreshToken(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	accId := 1
+	rt := model.RefreshToken{
+		FamilyId:  "test_family",
+		TokenHash: "test_hash",
+		ExpiredAt: time.Now().Add(time.Hour).UTC(),
+	}
+	_ = tc.SetRefreshToken(accId, &rt)
+
+	next := model.RefreshToken{
+		FamilyId:  rt.FamilyId,
+		TokenHash: "test_hash_next",
+		ExpiredAt: rt.ExpiredAt,
+	}
+
+	// Act
+	swapped, err := tc.SwapRefreshToken(accId, rt.TokenHash, &next)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, swapped)
+
+	res, _ := tc.GetRefreshToken(accId, rt.FamilyId)
+	assert.Equal(t, next.TokenHash, res.TokenHash)
+
+	// Stale hash
+	// Act
+	swapped, err = tc.SwapRefreshToken(accId, rt.TokenHash, &model.RefreshToken{
+		FamilyId:  rt.FamilyId,
+		TokenHash: "test_hash_fork",
+		ExpiredAt: rt.ExpiredAt,
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, swapped)
+
+	res, _ = tc.GetRefreshToken(accId, rt.FamilyId)
+	assert.Equal(t, next.TokenHash, res.TokenHash)
+
+	// Teardown
+	_ = tc.DeleteRefreshToken(accId, rt.FamilyId)
+}
+
+func TestCache_DeleteAllRefreshToken(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	accId := 1
+	familyIds := []string{"test_family_1", "test_family_2"}
+	for _, familyId := range familyIds {
+		_ = tc.SetRefreshToken(accId, &model.RefreshToken{
+			FamilyId:  familyId,
+			TokenHash: "test_hash",
+			ExpiredAt: time.Now().Add(time.Hour).UTC(),
+		})
+	}
+
+	// Act
+	err := tc.DeleteAllRefreshToken(accId)
+
+	// Assert
+	assert.Nil(t, err)
+	for _, familyId := range familyIds {
+		res, _ := tc.GetRefreshToken(accId, familyId)
+		assert.Nil(t, res)
+	}
+}
+
+func TestCache_RevokeToken(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	accId := 1
+	tokenHash := "test_revoke_token_hash"
+
+	// Act
+	err := tc.RevokeToken(accId, tokenHash, time.Minute)
+
+	// Assert
+	assert.Nil(t, err)
+
+	// Teardown
+	_ = rc.Del(token.GetRevokedTokenRedisKey(accId, tokenHash)).Err()
+}
+
+func TestCache_IsTokenRevoked(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	accId := 1
+	tokenHash := "test_is_revoked_token_hash"
+
+	// Not revoked
+	// Act
+	isRevoked, err := tc.IsTokenRevoked(accId, tokenHash)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, isRevoked)
+
+	// Revoked
+	_ = tc.RevokeToken(accId, tokenHash, time.Minute)
+
+	// Act
+	isRevoked, err = tc.IsTokenRevoked(accId, tokenHash)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, isRevoked)
+
+	// Teardown
+	_ = rc.Del(token.GetRevokedTokenRedisKey(accId, tokenHash)).Err()
+}
+
+func TestCache_SetSession(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	accId := 1
+	now := time.Now().UTC()
+	session := model.Session{
+		Id:         "test_session",
+		AccountId:  accId,
+		UserAgent:  "test-agent",
+		Ip:         "127.0.0.1",
+		IssuedAt:   now,
+		LastSeenAt: now,
+		ExpiredAt:  now.Add(time.Hour),
+	}
+
+	// Act
+	err := tc.SetSession(accId, &session)
+
+	// Assert
+	assert.Nil(t, err)
+
+	// Teardown
+	_ = tc.DeleteSession(accId, session.Id)
+}
+
+func TestCache_GetSession(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	accId := 1
+	now := time.Now().UTC().Truncate(time.Second)
+	session := model.Session{
+		Id:         "test_session",
+		AccountId:  accId,
+		IssuedAt:   now,
+		LastSeenAt: now,
+		ExpiredAt:  now.Add(time.Hour),
+	}
+	_ = tc.SetSession(accId, &session)
+	lastSeenAt := now.Add(time.Minute)
+	_ = tc.TouchSession(accId, session.Id, lastSeenAt)
+
+	// Act
+	res, err := tc.GetSession(accId, session.Id)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotNil(t, res)
+	assert.Equal(t, lastSeenAt.Unix(), res.LastSeenAt.Unix())
+
+	// No data
+	_ = tc.DeleteSession(accId, session.Id)
+
+	// Act
+	res, err = tc.GetSession(accId, session.Id)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, res)
+}
+
+func TestCache_FindSession(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	accId := 1
+	_ = tc.DeleteAllSession(accId)
+	now := time.Now().UTC()
+	sessionIds := []string{"test_session_1", "test_session_2"}
+	for _, sessionId := range sessionIds {
+		_ = tc.SetSession(accId, &model.Session{
+			Id:        sessionId,
+			AccountId: accId,
+			IssuedAt:  now,
+			ExpiredAt: now.Add(time.Hour),
+		})
+	}
+
+	// Act
+	sessions, err := tc.FindSession(accId)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 2)
+
+	// Teardown
+	_ = tc.DeleteAllSession(accId)
+}
+
+func TestCache_GetMfaChallenge(t *testing.T) {
+	// Arrange
+	rc, _ := driver.NewRedis()
+	tc := NewRedis(rc)
+
+	challenge := model.MfaChallenge{
+		Id:        "test_mfa_challenge",
+		AccountId: 1,
+		ExpiredAt: time.Now().UTC().Add(time.Minute),
+	}
+	_ = tc.SetMfaChallenge(&challenge, time.Minute)
+
+	// Act
+	res, err := tc.GetMfaChallenge(challenge.Id)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotNil(t, res)
+	assert.Equal(t, challenge.AccountId, res.AccountId)
+
+	// Attempt counter
+	cnt, err := tc.IncrMfaChallengeAttempt(challenge.Id, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cnt)
+
+	// Teardown
+	_ = tc.DeleteMfaChallenge(challenge.Id)
+	res, err = tc.GetMfaChallenge(challenge.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, res)
+}
@@ -3,8 +3,21 @@ package token
 import (
 	"oauth2-console-go/dto/apireq"
 	"oauth2-console-go/dto/apires"
+	"time"
 )
 
 type Service interface {
 	GenToken(req *apireq.GetSysAccountToken) (*apires.SysAccountToken, error)
+	VerifyMfa(req *apireq.VerifySysAccountMfa) (*apires.SysAccountToken, error)
+	RefreshToken(req *apireq.RefreshSysAccountToken) (*apires.SysAccountToken, error)
+	Logout(accId int, sessionId, tokenStr string, expiredAt time.Time, req *apireq.LogoutSysAccountToken) error
+	LogoutAll(accId int) error
+	ListSession(accId int, currentSessionId string) (*apires.ListSysAccountSession, error)
+	RevokeSession(accId int, sessionId string) error
+	ChangePassword(accId int, req *apireq.ChangePassword) (*apires.SysAccountToken, error)
+	UnlockAccount(accId int) error
+	DisableAccount(accId int) error
+	EnableAccount(accId int) error
+	ResetAccountDisabled(accId int) error
+	IsAccountDisabled(accId int) (bool, error)
 }
@@ -1,27 +1,38 @@
 package service
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"oauth2-console-go/config"
 	"oauth2-console-go/dto/apireq"
 	"oauth2-console-go/dto/apires"
 	"oauth2-console-go/dto/model"
+	"oauth2-console-go/internal/system/login_log"
+	mfaLibrary "oauth2-console-go/internal/system/mfa/library"
 	"oauth2-console-go/internal/system/sys_account"
 	"oauth2-console-go/internal/token"
 	tokenLibrary "oauth2-console-go/internal/token/library"
 	"oauth2-console-go/pkg/er"
 	"oauth2-console-go/pkg/helper"
+	"oauth2-console-go/pkg/logr"
+	"sort"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 type Service struct {
-	sysAccRepo sys_account.Repository
-	tokenCache token.Cache
+	sysAccRepo   sys_account.Repository
+	loginLogRepo login_log.Repository
+	tokenCache   token.Cache
 }
 
-func NewService(sar sys_account.Repository, tc token.Cache) token.Service {
+func NewService(sar sys_account.Repository, llr login_log.Repository, tc token.Cache) token.Service {
 	return &Service{
-		sysAccRepo: sar,
-		tokenCache: tc,
+		sysAccRepo:   sar,
+		loginLogRepo: llr,
+		tokenCache:   tc,
 	}
 }
 
@@ -32,31 +43,791 @@ func (s *Service) GenToken(req *apireq.GetSysAccountToken) (*apires.SysAccountTo
 		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
 		return nil, findErr
 	}
+
+	// 帳號已鎖定或登入失敗次數過多
+	reason, err := s.checkLoginGuard(req)
+	if err != nil {
+		if reason != "" {
+			s.logLogin(acc, req.Account, req.UserAgent, req.Ip, false, reason)
+		}
+		return nil, err
+	}
+
 	if acc == nil || acc.IsDisable {
+		reason = model.LoginReasonAccountNotFound
+		if acc != nil {
+			reason = model.LoginReasonAccountDisabled
+		}
+		s.logLogin(acc, req.Account, req.UserAgent, req.Ip, false, reason)
+		s.recordLoginFailure(req)
 		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "", nil)
 		return nil, authErr
 	}
 
 	// Password not matched
-	pw := helper.ScryptStr(req.Password)
-	if acc.Password != pw {
+	ok, needsRehash := helper.VerifyPassword(acc.Password, req.Password)
+	if !ok {
+		s.logLogin(acc, req.Account, req.UserAgent, req.Ip, false, model.LoginReasonPasswordMismatch)
+		s.recordLoginFailure(req)
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "", nil)
+		return nil, authErr
+	}
+
+	// 舊格式的 hash 於登入成功時更新，失敗不影響登入
+	if needsRehash {
+		s.rehashPassword(acc, req.Password)
+	}
+
+	// 尚未驗證 email，不計入登入失敗次數
+	if config.GetRequireVerifiedLogin() && acc.VerifyAt.IsZero() {
+		s.logLogin(acc, req.Account, req.UserAgent, req.Ip, false, model.LoginReasonUnverified)
+		forbiddenErr := er.NewAppErr(http.StatusForbidden, er.ForbiddenError, "email is not verified.", nil)
+		return nil, forbiddenErr
+	}
+
+	// 已啟用兩步驟驗證，先回傳 mfa token，驗證 code 後才簽發 token，失敗次數於驗證通過後才重新計算
+	if acc.MfaEnabled {
+		return s.createMfaChallenge(acc, req.UserAgent, req.Ip)
+	}
+
+	// 登入成功，重新計算帳號的失敗次數
+	err = s.tokenCache.DeleteLoginFailure(token.GetAccountLoginFailureRedisKey(req.Account))
+	if err != nil {
+		logr.L.Error("delete login failure error.", zap.String("error", err.Error()))
+	}
+
+	return s.createSession(acc, req.UserAgent, req.Ip)
+}
+
+func (s *Service) VerifyMfa(req *apireq.VerifySysAccountMfa) (*apires.SysAccountToken, error) {
+	challenge, err := s.tokenCache.GetMfaChallenge(req.MfaToken)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get mfa challenge error.", err)
+		return nil, redisErr
+	}
+	if challenge == nil || challenge.ExpiredAt.Before(time.Now().UTC()) {
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "mfa token is not valid.", nil)
+		return nil, authErr
+	}
+
+	// 限制同一個 mfa token 的嘗試次數
+	attempt, err := s.tokenCache.IncrMfaChallengeAttempt(challenge.Id, config.MfaChallengeExpireTime)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "incr mfa challenge attempt error.", err)
+		return nil, redisErr
+	}
+	if attempt > config.MfaChallengeMaxAttempt {
+		_ = s.tokenCache.DeleteMfaChallenge(challenge.Id)
+		limitErr := er.NewAppErr(http.StatusTooManyRequests, er.LimitExceededError, "too many mfa attempts.", nil)
+		return nil, limitErr
+	}
+
+	// Check Account Exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: challenge.AccountId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc == nil || acc.IsDisable || !acc.MfaEnabled {
+		_ = s.tokenCache.DeleteMfaChallenge(challenge.Id)
 		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "", nil)
 		return nil, authErr
 	}
 
-	oToken, expiredAt, err := tokenLibrary.GenToken(acc.Id)
+	// 與登入共用失敗次數及鎖定，避免重新取得 mfa token 無限次猜測 code
+	guardReq := &apireq.GetSysAccountToken{
+		Account:   acc.Account,
+		UserAgent: challenge.UserAgent,
+		Ip:        challenge.Ip,
+	}
+	reason, err := s.checkLoginGuard(guardReq)
+	if err != nil {
+		if reason != "" {
+			s.logLogin(acc, acc.Account, challenge.UserAgent, challenge.Ip, false, reason)
+		}
+		return nil, err
+	}
+
+	ok, isRecovery, counter, recoveryCodes := mfaLibrary.VerifyCode(acc, req.Code, time.Now())
+
+	// 同一個 TOTP code 不可重複使用，並行請求時只有一個能記錄成功
+	if ok && !isRecovery {
+		ok, err = s.sysAccRepo.UpdateTotpCounter(acc.Id, counter)
+		if err != nil {
+			updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+			return nil, updateErr
+		}
+	}
+	if !ok {
+		s.logLogin(acc, acc.Account, challenge.UserAgent, challenge.Ip, false, model.LoginReasonMfaFailed)
+		s.recordLoginFailure(guardReq)
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "mfa code is not valid.", nil)
+		return nil, authErr
+	}
+
+	// Recovery code 只能使用一次
+	if isRecovery {
+		acc.MfaRecoveryCodes = recoveryCodes
+		err = s.sysAccRepo.Update(acc, "mfa_recovery_codes")
+		if err != nil {
+			updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+			return nil, updateErr
+		}
+	}
+
+	err = s.tokenCache.DeleteMfaChallenge(challenge.Id)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete mfa challenge error.", err)
+		return nil, redisErr
+	}
+
+	// 兩步驟驗證通過，重新計算帳號的失敗次數
+	err = s.tokenCache.DeleteLoginFailure(token.GetAccountLoginFailureRedisKey(acc.Account))
+	if err != nil {
+		logr.L.Error("delete login failure error.", zap.String("error", err.Error()))
+	}
+
+	return s.createSession(acc, challenge.UserAgent, challenge.Ip)
+}
+
+func (s *Service) RefreshToken(req *apireq.RefreshSysAccountToken) (*apires.SysAccountToken, error) {
+	accId, familyId, err := tokenLibrary.ParseRefreshToken(req.RefreshToken)
+	if err != nil {
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "refresh token is not valid.", err)
+		return nil, authErr
+	}
+
+	rt, err := s.tokenCache.GetRefreshToken(accId, familyId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get refresh token error.", err)
+		return nil, redisErr
+	}
+	if rt == nil || rt.ExpiredAt.Before(time.Now().UTC()) {
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "refresh token is not valid.", nil)
+		return nil, authErr
+	}
+
+	// Refresh token 已被輪替過又再次使用，視為外洩，撤銷整個 family 及其 session
+	if !tokenLibrary.CheckRefreshToken(rt, req.RefreshToken) {
+		err = s.tokenCache.DeleteSession(accId, familyId)
+		if err != nil {
+			logr.L.Error("delete refresh token family error.", zap.String("error", err.Error()))
+		}
+
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "refresh token is not valid.", nil)
+		return nil, authErr
+	}
+
+	// Session 已被撤銷
+	session, err := s.tokenCache.GetSession(accId, familyId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get session error.", err)
+		return nil, redisErr
+	}
+	if session == nil {
+		_ = s.tokenCache.DeleteRefreshToken(accId, familyId)
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "refresh token is not valid.", nil)
+		return nil, authErr
+	}
+
+	// Check Account Exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc == nil || acc.IsDisable {
+		_ = s.tokenCache.DeleteSession(accId, familyId)
+		authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "", nil)
+		if acc != nil {
+			authErr = er.NewAppErr(http.StatusUnauthorized, er.AccountDisabledError, "account is disabled.", nil)
+		}
+		return nil, authErr
+	}
+
+	err = s.tokenCache.TouchSession(accId, session.Id, time.Now().UTC())
+	if err != nil {
+		logr.L.Error("touch session error.", zap.String("error", err.Error()))
+	}
+
+	// 輪替 refresh token，session 的有效期限不延長
+	return s.issueToken(acc, session, rt.TokenHash)
+}
+
+func (s *Service) Logout(accId int, sessionId, tokenStr string, expiredAt time.Time, req *apireq.LogoutSysAccountToken) error {
+	// 將目前的 token 加入撤銷名單，直到 token 過期
+	ttl := time.Until(expiredAt)
+	if ttl > 0 {
+		err := s.tokenCache.RevokeToken(accId, tokenLibrary.HashToken(tokenStr), ttl)
+		if err != nil {
+			redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "revoke token error.", err)
+			return redisErr
+		}
+	}
+
+	// 撤銷 session 及同一次登入的 refresh token
+	if sessionId != "" {
+		err := s.tokenCache.DeleteSession(accId, sessionId)
+		if err != nil {
+			redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete session error.", err)
+			return redisErr
+		}
+	}
+
+	// 未帶 session id 的舊版 token，由 request 帶入的 refresh token 撤銷
+	if req.RefreshToken == "" {
+		return nil
+	}
+
+	rtAccId, familyId, err := tokenLibrary.ParseRefreshToken(req.RefreshToken)
+	if err != nil || rtAccId != accId {
+		return nil
+	}
+
+	rt, err := s.tokenCache.GetRefreshToken(accId, familyId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get refresh token error.", err)
+		return redisErr
+	}
+	if rt == nil || !tokenLibrary.CheckRefreshToken(rt, req.RefreshToken) {
+		return nil
+	}
+
+	err = s.tokenCache.DeleteRefreshToken(accId, familyId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete refresh token error.", err)
+		return redisErr
+	}
+
+	return nil
+}
+
+func (s *Service) LogoutAll(accId int) error {
+	// 更新 server iat，所有在此之前簽發的 token 皆失效
+	iat := time.Now().UTC().Unix()
+	err := s.tokenCache.SetTokenIat(accId, iat)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "set token iat error.", err)
+		return redisErr
+	}
+
+	err = s.tokenCache.DeleteAllSession(accId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete session error.", err)
+		return redisErr
+	}
+
+	err = s.tokenCache.DeleteAllRefreshToken(accId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete refresh token error.", err)
+		return redisErr
+	}
+
+	return nil
+}
+
+func (s *Service) ListSession(accId int, currentSessionId string) (*apires.ListSysAccountSession, error) {
+	// Check account id exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc == nil || acc.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", err)
+		return nil, notFoundErr
+	}
+
+	sessions, err := s.tokenCache.FindSession(accId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "find session error.", err)
+		return nil, redisErr
+	}
+
+	now := time.Now().UTC()
+	list := make([]*apires.SysAccountSession, 0)
+	for _, session := range sessions {
+		if session.ExpiredAt.Before(now) {
+			continue
+		}
+		list = append(list, &apires.SysAccountSession{
+			Id:         session.Id,
+			UserAgent:  session.UserAgent,
+			Ip:         session.Ip,
+			IssuedAt:   session.IssuedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiredAt:  session.ExpiredAt,
+			IsCurrent:  session.Id == currentSessionId,
+		})
+	}
+
+	// 最近使用的 session 排在前面
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LastSeenAt.After(list[j].LastSeenAt)
+	})
+
+	res := apires.ListSysAccountSession{
+		List: list,
+	}
+
+	return &res, nil
+}
+
+func (s *Service) RevokeSession(accId int, sessionId string) error {
+	session, err := s.tokenCache.GetSession(accId, sessionId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get session error.", err)
+		return redisErr
+	}
+	if session == nil {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "session not found.", nil)
+		return notFoundErr
+	}
+
+	err = s.tokenCache.DeleteSession(accId, sessionId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete session error.", err)
+		return redisErr
+	}
+
+	return nil
+}
+
+func (s *Service) ChangePassword(accId int, req *apireq.ChangePassword) (*apires.SysAccountToken, error) {
+	// Check account id exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return nil, findErr
+	}
+	if acc == nil || acc.IsDisable {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return nil, notFoundErr
+	}
+
+	// 與登入共用失敗次數及鎖定，避免以修改密碼猜測目前的密碼
+	guardReq := &apireq.GetSysAccountToken{
+		Account:   acc.Account,
+		UserAgent: req.UserAgent,
+		Ip:        req.Ip,
+	}
+	reason, err := s.checkLoginGuard(guardReq)
+	if err != nil {
+		if reason != "" {
+			s.logLogin(acc, acc.Account, req.UserAgent, req.Ip, false, reason)
+		}
+		return nil, err
+	}
+
+	ok, _ := helper.VerifyPassword(acc.Password, req.CurrentPassword)
+	if !ok {
+		s.logLogin(acc, acc.Account, req.UserAgent, req.Ip, false, model.LoginReasonPasswordMismatch)
+		s.recordLoginFailure(guardReq)
+		paramErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "current password is not correct.", nil)
+		return nil, paramErr
+	}
+
+	err = s.tokenCache.DeleteLoginFailure(token.GetAccountLoginFailureRedisKey(acc.Account))
+	if err != nil {
+		logr.L.Error("delete login failure error.", zap.String("error", err.Error()))
+	}
+
+	pw, err := helper.HashPassword(req.NewPassword)
+	if err != nil {
+		unknownErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "hash password error.", err)
+		return nil, unknownErr
+	}
+
+	acc.Password = pw
+	err = s.sysAccRepo.Update(acc, "password")
+	if err != nil {
+		updateErr := er.NewAppErr(http.StatusInternalServerError, er.DBUpdateError, "update account error.", err)
+		return nil, updateErr
+	}
+
+	// 登出所有裝置，再為目前的裝置簽發新的 token
+	err = s.LogoutAll(accId)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.startSession(acc, req.UserAgent, req.Ip)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueToken(acc, session, "")
+}
+
+func (s *Service) UnlockAccount(accId int) error {
+	// Check account id exist
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return findErr
+	}
+	if acc == nil {
+		notFoundErr := er.NewAppErr(http.StatusBadRequest, er.ResourceNotFoundError, "account not found.", nil)
+		return notFoundErr
+	}
+
+	err = s.tokenCache.UnlockAccount(acc.Account)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "unlock account error.", err)
+		return redisErr
+	}
+
+	return nil
+}
+
+// DisableAccount 標記為停用，TokenAuth 會拒絕尚未過期的 token
+// 須在資料庫更新前呼叫，避免資料庫已停用但快取仍為啟用，token 撤銷由 LogoutAll 處理
+func (s *Service) DisableAccount(accId int) error {
+	err := s.tokenCache.SetAccountDisabled(accId, true)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "set account disabled error.", err)
+		return redisErr
+	}
+
+	return nil
+}
+
+func (s *Service) EnableAccount(accId int) error {
+	err := s.tokenCache.SetAccountDisabled(accId, false)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "set account disabled error.", err)
+		return redisErr
+	}
+
+	return nil
+}
+
+// ResetAccountDisabled 清除停用狀態的快取，改以資料庫為準，用於資料庫更新失敗時還原
+func (s *Service) ResetAccountDisabled(accId int) error {
+	err := s.tokenCache.DeleteAccountDisabled(accId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete account disabled error.", err)
+		return redisErr
+	}
+
+	return nil
+}
+
+// IsAccountDisabled 優先使用快取，未快取時(如停用功能上線前已停用的帳號)以資料庫為準並寫回快取
+func (s *Service) IsAccountDisabled(accId int) (bool, error) {
+	isDisable, found, err := s.tokenCache.GetAccountDisabled(accId)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get account disabled error.", err)
+		return false, redisErr
+	}
+	if found {
+		return isDisable, nil
+	}
+
+	acc, err := s.sysAccRepo.FindOne(&model.SysAccount{Id: accId})
+	if err != nil {
+		findErr := er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "find account error.", err)
+		return false, findErr
+	}
+
+	// 帳號不存在視為停用
+	isDisable = acc == nil || acc.IsDisable
+	err = s.tokenCache.SetAccountDisabled(accId, isDisable)
+	if err != nil {
+		logr.L.Error("set account disabled error.", zap.String("error", err.Error()))
+	}
+
+	return isDisable, nil
+}
+
+func (s *Service) rehashPassword(acc *model.SysAccount, password string) {
+	hash, err := helper.HashPassword(password)
+	if err != nil {
+		logr.L.Error("hash password error.", zap.String("error", err.Error()))
+		return
+	}
+
+	acc.Password = hash
+	err = s.sysAccRepo.Update(acc, "password")
+	if err != nil {
+		logr.L.Error("update password hash error.", zap.String("error", err.Error()))
+	}
+}
+
+// checkLoginGuard 被拒絕時 reason 為登入紀錄的失敗原因
+func (s *Service) checkLoginGuard(req *apireq.GetSysAccountToken) (reason string, err error) {
+	ttl, err := s.tokenCache.GetAccountLockTTL(req.Account)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get account lock error.", err)
+		return "", redisErr
+	}
+	if ttl > 0 {
+		msg := fmt.Sprintf("account is locked, retry after %d seconds.", int(math.Ceil(ttl.Seconds())))
+		lockedErr := er.NewAppErr(http.StatusLocked, er.AccountLockedError, msg, nil)
+		return model.LoginReasonAccountLocked, lockedErr
+	}
+
+	keys := []string{token.GetAccountLoginFailureRedisKey(req.Account)}
+	if req.Ip != "" {
+		keys = append(keys, token.GetIpLoginFailureRedisKey(req.Ip))
+	}
+
+	now := time.Now().UTC()
+	for i, key := range keys {
+		lf, err := s.tokenCache.GetLoginFailure(key)
+		if err != nil {
+			redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get login failure error.", err)
+			return "", redisErr
+		}
+		if lf == nil {
+			continue
+		}
+
+		// 同一 IP 失敗次數過多，於累計期間內拒絕登入
+		retryAfter := tokenLibrary.LoginRetryAfter(lf, now)
+		if i > 0 && lf.Count >= config.LoginIpLockThreshold {
+			retryAfter = config.LoginFailureWindow
+		}
+
+		if retryAfter > 0 {
+			msg := fmt.Sprintf("too many failed login attempts, retry after %d seconds.", int(math.Ceil(retryAfter.Seconds())))
+			limitErr := er.NewAppErr(http.StatusTooManyRequests, er.LimitExceededError, msg, nil)
+			return model.LoginReasonTooManyAttempts, limitErr
+		}
+	}
+
+	return "", nil
+}
+
+func (s *Service) recordLoginFailure(req *apireq.GetSysAccountToken) {
+	now := time.Now().UTC()
+
+	if req.Ip != "" {
+		_, err := s.tokenCache.IncrLoginFailure(token.GetIpLoginFailureRedisKey(req.Ip), now, config.LoginFailureWindow)
+		if err != nil {
+			logr.L.Error("incr ip login failure error.", zap.String("error", err.Error()))
+		}
+	}
+
+	key := token.GetAccountLoginFailureRedisKey(req.Account)
+	lf, err := s.tokenCache.IncrLoginFailure(key, now, config.LoginFailureWindow)
+	if err != nil {
+		logr.L.Error("incr account login failure error.", zap.String("error", err.Error()))
+		return
+	}
+
+	// 失敗次數達門檻，暫時鎖定帳號
+	if lf.Count >= config.LoginLockThreshold {
+		err = s.tokenCache.LockAccount(req.Account, config.LoginLockTime)
+		if err != nil {
+			logr.L.Error("lock account error.", zap.String("error", err.Error()))
+			return
+		}
+		_ = s.tokenCache.DeleteLoginFailure(key)
+	}
+}
+
+func (s *Service) createMfaChallenge(acc *model.SysAccount, userAgent, ip string) (*apires.SysAccountToken, error) {
+	challengeId, err := helper.RandomUrlSafe(32)
 	if err != nil {
 		tokenErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "", err)
 		return nil, tokenErr
 	}
 
-	// Set iat
-	iat := time.Now().UTC().Unix()
-	_ = s.tokenCache.SetTokenIat(acc.Id, iat)
+	expiredAt := time.Now().UTC().Add(config.MfaChallengeExpireTime)
+	err = s.tokenCache.SetMfaChallenge(&model.MfaChallenge{
+		Id:        challengeId,
+		AccountId: acc.Id,
+		UserAgent: userAgent,
+		Ip:        ip,
+		ExpiredAt: expiredAt,
+	}, config.MfaChallengeExpireTime)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "set mfa challenge error.", err)
+		return nil, redisErr
+	}
+
+	res := apires.SysAccountToken{
+		MfaRequired:       true,
+		MfaToken:          challengeId,
+		MfaTokenExpiredAt: &expiredAt,
+		Data:              map[string]interface{}{},
+	}
+
+	return &res, nil
+}
+
+func (s *Service) createSession(acc *model.SysAccount, userAgent, ip string) (*apires.SysAccountToken, error) {
+	session, err := s.startSession(acc, userAgent, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	// 上一次成功登入的時間，須在寫入本次紀錄前取得
+	lastLogin, err := s.loginLogRepo.FindLastSuccess(acc.Id)
+	if err != nil {
+		logr.L.Error("find last login error.", zap.String("error", err.Error()))
+	}
+	s.logLogin(acc, acc.Account, userAgent, ip, true, "")
+
+	res, err := s.issueToken(acc, session, "")
+	if err != nil {
+		return nil, err
+	}
+
+	res.Data["last_login_at"] = nil
+	res.Data["last_login_ip"] = ""
+	if lastLogin != nil {
+		res.Data["last_login_at"] = lastLogin.CreatedAt
+		res.Data["last_login_ip"] = lastLogin.Ip
+	}
+
+	return res, nil
+}
+
+// startSession 建立新的 session
+func (s *Service) startSession(acc *model.SysAccount, userAgent, ip string) (*model.Session, error) {
+	sessionId, err := tokenLibrary.GenSessionId()
+	if err != nil {
+		tokenErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "", err)
+		return nil, tokenErr
+	}
+
+	now := time.Now().UTC()
+	session := model.Session{
+		Id:         sessionId,
+		AccountId:  acc.Id,
+		UserAgent:  userAgent,
+		Ip:         ip,
+		IssuedAt:   now,
+		LastSeenAt: now,
+		ExpiredAt:  now.Add(config.RefreshTokenExpireTime),
+	}
+
+	err = s.tokenCache.SetSession(acc.Id, &session)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "set session error.", err)
+		return nil, redisErr
+	}
+
+	return &session, nil
+}
+
+// logLogin 寫入登入紀錄，失敗不影響登入流程
+func (s *Service) logLogin(acc *model.SysAccount, account, userAgent, ip string, isSuccess bool, reason string) {
+	m := model.SysAccountLoginLog{
+		Account:   account,
+		Ip:        ip,
+		UserAgent: userAgent,
+		IsSuccess: isSuccess,
+		Reason:    reason,
+	}
+	if acc != nil {
+		m.SysAccountId = acc.Id
+	}
+	// 依欄位長度截斷
+	if r := []rune(m.UserAgent); len(r) > 255 {
+		m.UserAgent = string(r[:255])
+	}
+	if r := []rune(m.Account); len(r) > 64 {
+		m.Account = string(r[:64])
+	}
+
+	err := s.loginLogRepo.Insert(&m)
+	if err != nil {
+		logr.L.Error("insert login log error.", zap.String("error", err.Error()))
+	}
+}
+
+// issueToken 簽發 token 及 refresh token，prevHash 不為空時以 compare-and-swap 輪替既有的 refresh token
+func (s *Service) issueToken(acc *model.SysAccount, session *model.Session, prevHash string) (*apires.SysAccountToken, error) {
+	// 帳號要求兩步驟驗證但尚未設定，只簽發設定用的 token
+	if acc.MfaRequired && !acc.MfaEnabled {
+		return s.issueMfaEnrollToken(acc, session)
+	}
+
+	oToken, expiredAt, err := tokenLibrary.GenToken(acc.Id, acc.Role, session.Id)
+	if err != nil {
+		tokenErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "", err)
+		return nil, tokenErr
+	}
+
+	refreshToken, err := tokenLibrary.GenRefreshToken(acc.Id, session.Id)
+	if err != nil {
+		tokenErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "", err)
+		return nil, tokenErr
+	}
+
+	// Set refresh token
+	rt := &model.RefreshToken{
+		FamilyId:  session.Id,
+		TokenHash: tokenLibrary.HashRefreshToken(refreshToken),
+		ExpiredAt: session.ExpiredAt,
+	}
+	if prevHash == "" {
+		err = s.tokenCache.SetRefreshToken(acc.Id, rt)
+		if err != nil {
+			redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "set refresh token error.", err)
+			return nil, redisErr
+		}
+	} else {
+		var swapped bool
+		swapped, err = s.tokenCache.SwapRefreshToken(acc.Id, prevHash, rt)
+		if err != nil {
+			redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "set refresh token error.", err)
+			return nil, redisErr
+		}
+
+		// 同一個 refresh token 已被另一個請求輪替，視為重複使用，撤銷整個 family 及其 session
+		if !swapped {
+			err = s.tokenCache.DeleteSession(acc.Id, session.Id)
+			if err != nil {
+				logr.L.Error("delete refresh token family error.", zap.String("error", err.Error()))
+			}
+
+			authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "refresh token is not valid.", nil)
+			return nil, authErr
+		}
+	}
+
+	mapData := map[string]interface{}{}
+	mapData["name"] = acc.Name
+	mapData["email"] = acc.Email
+	mapData["role"] = acc.Role
+	mapData["mfa_enabled"] = acc.MfaEnabled
+
+	res := apires.SysAccountToken{
+		Token:                 oToken,
+		ExpiredAt:             expiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiredAt: session.ExpiredAt,
+		Data:                  mapData,
+	}
+
+	return &res, nil
+}
+
+// issueMfaEnrollToken 簽發僅能設定兩步驟驗證的 token，不發 refresh token，設定完成後需重新登入
+func (s *Service) issueMfaEnrollToken(acc *model.SysAccount, session *model.Session) (*apires.SysAccountToken, error) {
+	oToken, expiredAt, err := tokenLibrary.GenMfaEnrollToken(acc.Id, acc.Role, session.Id)
+	if err != nil {
+		tokenErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "", err)
+		return nil, tokenErr
+	}
+
+	// 帳號改為要求兩步驟驗證前已簽發的 refresh token 不可再使用
+	err = s.tokenCache.DeleteRefreshToken(acc.Id, session.Id)
+	if err != nil {
+		redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "delete refresh token error.", err)
+		return nil, redisErr
+	}
 
 	mapData := map[string]interface{}{}
 	mapData["name"] = acc.Name
 	mapData["email"] = acc.Email
+	mapData["role"] = acc.Role
+	mapData["mfa_enabled"] = acc.MfaEnabled
+	mapData["mfa_enroll_required"] = true
 
 	res := apires.SysAccountToken{
 		Token:     oToken,
@@ -5,13 +5,20 @@ import (
 	"oauth2-console-go/config"
 	"oauth2-console-go/driver"
 	"oauth2-console-go/dto/apireq"
+	"oauth2-console-go/dto/model"
+	loginLogRepo "oauth2-console-go/internal/system/login_log/repository"
 	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
+	"oauth2-console-go/internal/token"
+	tokenLibrary "oauth2-console-go/internal/token/library"
 	tokenRepo "oauth2-console-go/internal/token/repository"
 	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/totp"
 	"oauth2-console-go/pkg/valider"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -34,8 +41,9 @@ func TestService_GenToken(t *testing.T) {
 	orm, _ := driver.NewXorm()
 	rc, _ := driver.NewRedis()
 	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
 	tc := tokenRepo.NewRedis(rc)
-	ts := NewService(sar, tc)
+	ts := NewService(sar, llr, tc)
 
 	// No data
 	req := apireq.GetSysAccountToken{
@@ -65,4 +73,417 @@ func TestService_GenToken(t *testing.T) {
 	// Assert
 	assert.Nil(t, err)
 	assert.NotNil(t, res)
+
+	assert.Contains(t, res.Data, "last_login_at")
+
+	// 舊格式的密碼 hash 於登入後更新
+	acc, _ := sar.FindOne(&model.SysAccount{Account: req.Account})
+	assert.True(t, strings.HasPrefix(acc.Password, "$argon2id$"))
+}
+
+func TestService_GenToken_MfaRequired(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	acc, _ := sar.FindOne(&model.SysAccount{Id: 1})
+	acc.MfaRequired = true
+	_ = sar.Update(acc, "mfa_required")
+
+	// Act
+	res, err := ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  "sys_account",
+		Password: "A12345678",
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.Token)
+	assert.Empty(t, res.RefreshToken)
+	assert.Equal(t, true, res.Data["mfa_enroll_required"])
+
+	claims, err := tokenLibrary.ParseToken(res.Token)
+	assert.Nil(t, err)
+	assert.True(t, tokenLibrary.IsMfaEnrollToken(claims))
+
+	// Teardown
+	acc.MfaRequired = false
+	_ = sar.Update(acc, "mfa_required")
+}
+
+func TestService_RefreshToken(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	loginRes, _ := ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  "sys_account",
+		Password: "A12345678",
+	})
+
+	// Act
+	res, err := ts.RefreshToken(&apireq.RefreshSysAccountToken{RefreshToken: loginRes.RefreshToken})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotNil(t, res)
+	assert.NotEqual(t, loginRes.RefreshToken, res.RefreshToken)
+
+	// Reuse rotated refresh token
+	// Act
+	_, err = ts.RefreshToken(&apireq.RefreshSysAccountToken{RefreshToken: loginRes.RefreshToken})
+
+	// Assert
+	assert.NotNil(t, err)
+	authErr := err.(*er.AppError)
+	assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+
+	// Whole family is revoked after reuse
+	// Act
+	_, err = ts.RefreshToken(&apireq.RefreshSysAccountToken{RefreshToken: res.RefreshToken})
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+func TestService_Logout(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	loginRes, _ := ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  "sys_account",
+		Password: "A12345678",
+	})
+	accId := 1
+	claims, _ := tokenLibrary.ParseToken(loginRes.Token)
+	sessionId := claims["jti"].(string)
+
+	// Act
+	err := ts.Logout(accId, sessionId, loginRes.Token, loginRes.ExpiredAt, &apireq.LogoutSysAccountToken{})
+
+	// Assert
+	assert.Nil(t, err)
+	isRevoked, _ := tc.IsTokenRevoked(accId, tokenLibrary.HashToken(loginRes.Token))
+	assert.True(t, isRevoked)
+	session, _ := tc.GetSession(accId, sessionId)
+	assert.Nil(t, session)
+	_, err = ts.RefreshToken(&apireq.RefreshSysAccountToken{RefreshToken: loginRes.RefreshToken})
+	assert.NotNil(t, err)
+}
+
+func TestService_LogoutAll(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	loginRes, _ := ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  "sys_account",
+		Password: "A12345678",
+	})
+	accId := 1
+
+	// Act
+	err := ts.LogoutAll(accId)
+
+	// Assert
+	assert.Nil(t, err)
+	iat, _ := tc.GetTokenIat(accId)
+	assert.GreaterOrEqual(t, int64(iat), time.Now().UTC().Unix()-1)
+	_, err = ts.RefreshToken(&apireq.RefreshSysAccountToken{RefreshToken: loginRes.RefreshToken})
+	assert.NotNil(t, err)
+}
+
+func TestService_DisableAccount(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	loginRes, _ := ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  "sys_account",
+		Password: "A12345678",
+	})
+	accId := 1
+
+	// Act
+	err := ts.DisableAccount(accId)
+	_ = ts.LogoutAll(accId)
+
+	// Assert
+	assert.Nil(t, err)
+	isDisabled, _ := ts.IsAccountDisabled(accId)
+	assert.True(t, isDisabled)
+	_, err = ts.RefreshToken(&apireq.RefreshSysAccountToken{RefreshToken: loginRes.RefreshToken})
+	assert.NotNil(t, err)
+
+	// Teardown
+	err = ts.EnableAccount(accId)
+	assert.Nil(t, err)
+	isDisabled, _ = ts.IsAccountDisabled(accId)
+	assert.False(t, isDisabled)
+
+	// 未快取時以資料庫為準，未停用的結果只短暫快取
+	err = ts.ResetAccountDisabled(accId)
+	assert.Nil(t, err)
+	isDisabled, err = ts.IsAccountDisabled(accId)
+	assert.Nil(t, err)
+	assert.False(t, isDisabled)
+	_, found, _ := tc.GetAccountDisabled(accId)
+	assert.True(t, found)
+	ttl, _ := rc.TTL(token.GetAccountDisabledRedisKey(accId)).Result()
+	assert.True(t, ttl <= config.AccountEnableCacheTime)
+}
+
+func TestService_ListSession(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	accId := 1
+	_ = ts.LogoutAll(accId)
+	for i := 0; i < 2; i++ {
+		_, _ = ts.GenToken(&apireq.GetSysAccountToken{
+			Account:   "sys_account",
+			Password:  "A12345678",
+			UserAgent: "test-agent",
+			Ip:        "127.0.0.1",
+		})
+	}
+
+	// Act
+	res, err := ts.ListSession(accId, "")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, res.List, 2)
+	assert.Equal(t, "test-agent", res.List[0].UserAgent)
+	assert.Equal(t, "127.0.0.1", res.List[0].Ip)
+
+	// Teardown
+	_ = ts.LogoutAll(accId)
+}
+
+func TestService_RevokeSession(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	accId := 1
+	loginRes, _ := ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  "sys_account",
+		Password: "A12345678",
+	})
+	claims, _ := tokenLibrary.ParseToken(loginRes.Token)
+	sessionId := claims["jti"].(string)
+
+	// Act
+	err := ts.RevokeSession(accId, sessionId)
+
+	// Assert
+	assert.Nil(t, err)
+	_, err = ts.RefreshToken(&apireq.RefreshSysAccountToken{RefreshToken: loginRes.RefreshToken})
+	assert.NotNil(t, err)
+
+	// No data
+	// Act
+	err = ts.RevokeSession(accId, sessionId)
+
+	// Assert
+	assert.NotNil(t, err)
+	notFoundErr := err.(*er.AppError)
+	assert.Equal(t, strconv.Itoa(er.ResourceNotFoundError), notFoundErr.Code)
+}
+
+func TestService_GenToken_LoginGuard(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	req := apireq.GetSysAccountToken{
+		Account:  "login_guard_account",
+		Password: "123456",
+	}
+	_ = tc.DeleteLoginFailure(token.GetAccountLoginFailureRedisKey(req.Account))
+
+	// Act
+	for i := 0; i < config.LoginDelayThreshold; i++ {
+		_, _ = ts.GenToken(&req)
+	}
+	_, err := ts.GenToken(&req)
+
+	// Assert
+	limitErr := err.(*er.AppError)
+	assert.Equal(t, http.StatusTooManyRequests, limitErr.StatusCode)
+	assert.Equal(t, strconv.Itoa(er.LimitExceededError), limitErr.Code)
+
+	// Locked account
+	_ = tc.LockAccount("sys_account", time.Minute)
+	_, err = ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  "sys_account",
+		Password: "A12345678",
+	})
+	lockedErr := err.(*er.AppError)
+	assert.Equal(t, http.StatusLocked, lockedErr.StatusCode)
+	assert.Equal(t, strconv.Itoa(er.AccountLockedError), lockedErr.Code)
+
+	// Unlock
+	err = ts.UnlockAccount(1)
+	assert.Nil(t, err)
+	res, err := ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  "sys_account",
+		Password: "A12345678",
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, res)
+
+	// Teardown
+	_ = tc.DeleteLoginFailure(token.GetAccountLoginFailureRedisKey(req.Account))
+}
+
+func TestService_VerifyMfa_LoginGuard(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	acc, _ := sar.FindOne(&model.SysAccount{Id: 1})
+	secret, _ := totp.GenerateSecret()
+	acc.TotpSecret = secret
+	acc.MfaEnabled = true
+	_ = sar.Update(acc, "totp_secret", "mfa_enabled")
+
+	key := token.GetAccountLoginFailureRedisKey(acc.Account)
+	_ = tc.DeleteLoginFailure(key)
+
+	verifyWithFreshChallenge := func(i int) error {
+		challengeId := "test_mfa_guard_" + strconv.Itoa(i)
+		_ = tc.SetMfaChallenge(&model.MfaChallenge{
+			Id:        challengeId,
+			AccountId: acc.Id,
+			ExpiredAt: time.Now().UTC().Add(config.MfaChallengeExpireTime),
+		}, config.MfaChallengeExpireTime)
+		_, err := ts.VerifyMfa(&apireq.VerifySysAccountMfa{MfaToken: challengeId, Code: "000000"})
+		return err
+	}
+
+	// Act，每次都以新的 mfa token 驗證錯誤的 code
+	for i := 0; i < config.LoginLockThreshold; i++ {
+		err := verifyWithFreshChallenge(i)
+		assert.NotNil(t, err)
+
+		// 略過漸進式等待時間
+		if lf, _ := tc.GetLoginFailure(key); lf != nil {
+			_ = rc.HSet(key, "last_failed_at", time.Now().Add(-config.LoginMaxDelay).Unix()).Err()
+		}
+	}
+	err := verifyWithFreshChallenge(config.LoginLockThreshold)
+
+	// Assert
+	lockedErr := err.(*er.AppError)
+	assert.Equal(t, http.StatusLocked, lockedErr.StatusCode)
+	assert.Equal(t, strconv.Itoa(er.AccountLockedError), lockedErr.Code)
+
+	// 密碼正確也無法在鎖定期間取得新的 mfa token
+	_, err = ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  acc.Account,
+		Password: "A12345678",
+	})
+	lockedErr = err.(*er.AppError)
+	assert.Equal(t, http.StatusLocked, lockedErr.StatusCode)
+
+	// Teardown
+	_ = ts.UnlockAccount(acc.Id)
+	_ = tc.DeleteLoginFailure(key)
+	for i := 0; i <= config.LoginLockThreshold; i++ {
+		_ = tc.DeleteMfaChallenge("test_mfa_guard_" + strconv.Itoa(i))
+	}
+	acc.TotpSecret = ""
+	acc.MfaEnabled = false
+	_ = sar.Update(acc, "totp_secret", "mfa_enabled")
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	// Arrange
+	orm, _ := driver.NewXorm()
+	rc, _ := driver.NewRedis()
+	sar := sysAccRepo.NewRepository(orm)
+	llr := loginLogRepo.NewRepository(orm)
+	tc := tokenRepo.NewRedis(rc)
+	ts := NewService(sar, llr, tc)
+
+	loginRes, _ := ts.GenToken(&apireq.GetSysAccountToken{
+		Account:  "sys_account",
+		Password: "A12345678",
+	})
+	claims, _ := tokenLibrary.ParseToken(loginRes.Token)
+	oldSessionId := claims["jti"].(string)
+
+	// Wrong current password
+	_, err := ts.ChangePassword(1, &apireq.ChangePassword{
+		CurrentPassword: "123456",
+		NewPassword:     "B12345678",
+	})
+	paramErr := err.(*er.AppError)
+	assert.Equal(t, strconv.Itoa(er.ErrorParamInvalid), paramErr.Code)
+
+	// 錯誤的目前密碼計入登入失敗次數
+	lf, _ := tc.GetLoginFailure(token.GetAccountLoginFailureRedisKey("sys_account"))
+	assert.NotNil(t, lf)
+	assert.Equal(t, 1, lf.Count)
+
+	// Act
+	res, err := ts.ChangePassword(1, &apireq.ChangePassword{
+		CurrentPassword: "A12345678",
+		NewPassword:     "B12345678",
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.Token)
+
+	// 其他 session 已被登出
+	session, _ := tc.GetSession(1, oldSessionId)
+	assert.Nil(t, session)
+
+	// Teardown
+	_, err = ts.ChangePassword(1, &apireq.ChangePassword{
+		CurrentPassword: "B12345678",
+		NewPassword:     "A12345678",
+	})
+	assert.Nil(t, err)
 }
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"oauth2-console-go/api"
 	"oauth2-console-go/config"
+	tokenLibrary "oauth2-console-go/internal/token/library"
 	"oauth2-console-go/pkg/logr"
 	"oauth2-console-go/pkg/valider"
 	"oauth2-console-go/route"
@@ -20,6 +21,12 @@ var port string
 // @termsOfService https://github.com/pinkeyu7/oauth2-console-go
 // @license.name MIT
 // @license.url
+// @securityDefinitions.apikey Bearer
+// @in header
+// @name Authorization
+// @securityDefinitions.apikey ApiKey
+// @in header
+// @name X-Api-Key
 func main() {
 	// init http port
 	flag.StringVar(&port, "port", "8080", "Initial port number")
@@ -34,16 +41,34 @@ func main() {
 	// init validation
 	valider.Init()
 
+	// init jwt signing keys
+	err := tokenLibrary.InitKeySet()
+	if err != nil {
+		log.Panicln(err)
+	}
+
 	// init driver
 	_ = api.InitXorm()
 	_ = api.InitRedis()
 	_ = api.InitRedisCluster()
 
+	// init storage，設定錯誤時不啟動
+	_, err = api.InitStorage()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	// init mailer，設定錯誤或正式環境使用 outbox 時不啟動
+	_, err = api.InitMailer()
+	if err != nil {
+		log.Panicln(err)
+	}
+
 	// init gin router
 	r := route.Init()
 
 	// start server
-	err := r.Run(":" + port)
+	err = r.Run(":" + port)
 	if err != nil {
 		log.Println(err)
 	}
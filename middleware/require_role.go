@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+	sysAccLibrary "oauth2-console-go/internal/system/sys_account/library"
+	"oauth2-console-go/pkg/er"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole 限制具有 role 以上權限的帳號才能使用，須在 TokenAuth 之後使用
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !sysAccLibrary.HasRole(c.GetString("role"), role) {
+			forbiddenErr := er.NewAppErr(http.StatusForbidden, er.ForbiddenError, "role is not allowed to access this route.", nil)
+			c.AbortWithStatusJSON(forbiddenErr.GetStatus(), forbiddenErr.GetMsg())
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -3,17 +3,36 @@ package middleware
 import (
 	"net/http"
 	"oauth2-console-go/api"
+	apiKeyLibrary "oauth2-console-go/internal/system/api_key/library"
+	apiKeyRepo "oauth2-console-go/internal/system/api_key/repository"
+	apiKeySrv "oauth2-console-go/internal/system/api_key/service"
+	loginLogRepo "oauth2-console-go/internal/system/login_log/repository"
+	sysAccRepo "oauth2-console-go/internal/system/sys_account/repository"
 	tokenLibrary "oauth2-console-go/internal/token/library"
 	tokenRepo "oauth2-console-go/internal/token/repository"
+	tokenSrv "oauth2-console-go/internal/token/service"
 	"oauth2-console-go/pkg/er"
+	"oauth2-console-go/pkg/helper"
 	"strconv"
+	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 )
 
 func TokenAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.Request.Header.Get("Bearer")
+		// 自動化流程使用 api key 取代 jwt
+		if apiKey := helper.GetApiKey(c.Request); apiKey != "" {
+			apiKeyAuth(c, apiKey)
+			return
+		}
+
+		token, isLegacy := helper.GetBearerToken(c.Request)
+		if isLegacy {
+			// 舊版 Bearer header 於淘汰期間仍可使用，請改用 Authorization: Bearer <jwt>
+			c.Header("Deprecation", "true")
+		}
 		if token == "" {
 			authErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "token is required.", nil)
 			c.AbortWithStatusJSON(authErr.GetStatus(), authErr.GetMsg())
@@ -41,17 +60,123 @@ func TokenAuth() gin.HandlerFunc {
 		// Jwt token state management
 		env := api.GetEnv()
 		tc := tokenRepo.NewRedis(env.RedisCluster)
-		serverIat, _ := tc.GetTokenIat(accId)
+
+		// 已停用的帳號，停用時同時更新 server iat，須先於 iat 檢查，無法確認時拒絕
+		sar := sysAccRepo.NewRepository(env.Orm)
+		llr := loginLogRepo.NewRepository(env.Orm)
+		ts := tokenSrv.NewService(sar, llr, tc)
+		isDisabled, err := ts.IsAccountDisabled(accId)
+		if err != nil {
+			appErr, ok := err.(*er.AppError)
+			if !ok {
+				appErr = er.NewAppErr(http.StatusInternalServerError, er.UnknownError, "", err)
+			}
+			c.AbortWithStatusJSON(appErr.GetStatus(), appErr.GetMsg())
+			return
+		}
+		if isDisabled {
+			disabledErr := er.NewAppErr(http.StatusUnauthorized, er.AccountDisabledError, "account is disabled.", nil)
+			c.AbortWithStatusJSON(disabledErr.GetStatus(), disabledErr.GetMsg())
+			return
+		}
+
+		serverIat, err := tc.GetTokenIat(accId)
+		if err != nil {
+			redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get token iat error.", err)
+			c.AbortWithStatusJSON(redisErr.GetStatus(), redisErr.GetMsg())
+			return
+		}
 		if jwtIat < serverIat {
 			iatErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "token is expired.", nil)
 			c.AbortWithStatusJSON(iatErr.GetStatus(), iatErr.GetMsg())
 			return
 		}
 
+		// 已登出的 token
+		isRevoked, err := tc.IsTokenRevoked(accId, tokenLibrary.HashToken(token))
+		if err != nil {
+			redisErr := er.NewAppErr(http.StatusInternalServerError, er.RedisSerError, "get revoked token error.", err)
+			c.AbortWithStatusJSON(redisErr.GetStatus(), redisErr.GetMsg())
+			return
+		}
+		if isRevoked {
+			revokedErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "token is revoked.", nil)
+			c.AbortWithStatusJSON(revokedErr.GetStatus(), revokedErr.GetMsg())
+			return
+		}
+
+		// Session state management，舊版未帶 jti 的 token 只檢查 iat
+		if jwtSessionId, ok := claims["jti"].(string); ok && jwtSessionId != "" {
+			now := time.Now().UTC()
+			session, err := tc.GetSession(accId, jwtSessionId)
+			if err != nil || session == nil || session.ExpiredAt.Before(now) {
+				sessionErr := er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "session is revoked.", err)
+				c.AbortWithStatusJSON(sessionErr.GetStatus(), sessionErr.GetMsg())
+				return
+			}
+
+			// 降低寫入頻率，最後使用時間每分鐘更新一次
+			if now.Sub(session.LastSeenAt) > time.Minute {
+				_ = tc.TouchSession(accId, jwtSessionId, now)
+			}
+
+			c.Set("session_id", jwtSessionId)
+		}
+
+		// 帳號要求兩步驟驗證但尚未啟用，只能存取設定兩步驟驗證的路由
+		if tokenLibrary.IsMfaEnrollToken(claims) && !tokenLibrary.IsMfaEnrollPathAllowed(c.Request.URL.Path) {
+			mfaErr := er.NewAppErr(http.StatusForbidden, er.ForbiddenError, "mfa enrollment is required.", nil)
+			c.AbortWithStatusJSON(mfaErr.GetStatus(), mfaErr.GetMsg())
+			return
+		}
+
+		// 舊版未帶 role 的 token 視為沒有任何角色，需重新取得 token
+		jwtRole, _ := claims["role"].(string)
+
 		// Set claims
 		c.Set("claims", claims)
 		c.Set("account_id", accId)
+		c.Set("role", jwtRole)
+		c.Set("token", token)
 
 		c.Next()
 	}
 }
+
+func apiKeyAuth(c *gin.Context, apiKey string) {
+	env := api.GetEnv()
+	sar := sysAccRepo.NewRepository(env.Orm)
+	akr := apiKeyRepo.NewRepository(env.Orm)
+	aks := apiKeySrv.NewService(sar, akr)
+
+	key, acc, err := aks.Authenticate(apiKey, c.ClientIP())
+	if err != nil {
+		authErr, ok := err.(*er.AppError)
+		if !ok {
+			authErr = er.NewAppErr(http.StatusUnauthorized, er.UnauthorizedError, "api key is not valid.", err)
+		}
+		c.AbortWithStatusJSON(authErr.GetStatus(), authErr.GetMsg())
+		return
+	}
+
+	// 唯讀及路由群組限制
+	if !apiKeyLibrary.IsRequestAllowed(key, c.Request.Method, c.Request.URL.Path) {
+		forbiddenErr := er.NewAppErr(http.StatusForbidden, er.ForbiddenError, "api key is not allowed to access this route.", nil)
+		c.AbortWithStatusJSON(forbiddenErr.GetStatus(), forbiddenErr.GetMsg())
+		return
+	}
+
+	// 與 jwt 相同的 claims，沿用 CheckJWTAccountId 驗證帳號
+	claims := jwt.MapClaims{
+		"account_id": strconv.Itoa(key.SysAccountId),
+		"role":       acc.Role,
+		"api_key_id": key.Id,
+	}
+
+	c.Set("claims", claims)
+	c.Set("account_id", key.SysAccountId)
+	c.Set("role", acc.Role)
+	c.Set("api_key_id", key.Id)
+
+	c.Next()
+}
@@ -0,0 +1,12 @@
+-- +migrate Up
+ALTER TABLE `sys_account`
+    ADD COLUMN `totp_secret` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `forgot_pass_token_expired_at`,
+    ADD COLUMN `mfa_recovery_codes` TEXT COLLATE utf8mb4_unicode_ci NOT NULL AFTER `totp_secret`,
+    ADD COLUMN `mfa_enabled` tinyint(4) NOT NULL DEFAULT '0' COMMENT '0:未啟用 1:已啟用' AFTER `mfa_recovery_codes`,
+    ADD COLUMN `mfa_required` tinyint(4) NOT NULL DEFAULT '0' COMMENT '0:選用 1:強制啟用' AFTER `mfa_enabled`;
+-- +migrate Down
+ALTER TABLE `sys_account`
+    DROP COLUMN `totp_secret`,
+    DROP COLUMN `mfa_recovery_codes`,
+    DROP COLUMN `mfa_enabled`,
+    DROP COLUMN `mfa_required`;
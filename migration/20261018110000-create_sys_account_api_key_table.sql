@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE `sys_account_api_key` (
+    `id` bigint(20) NOT NULL AUTO_INCREMENT,
+    `sys_account_id` bigint(20) NOT NULL COMMENT 'ref:sys_account.id',
+    `name` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `prefix` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `key_hash` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `read_only` tinyint(4) NOT NULL DEFAULT '0' COMMENT '0:可讀寫 1:唯讀',
+    `route_groups` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '逗號分隔，空值表示不限制',
+    `expired_at` datetime DEFAULT NULL,
+    `last_used_at` datetime DEFAULT NULL,
+    `last_used_ip` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_prefix` (`prefix`),
+    KEY `idx_sys_account_id` (`sys_account_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +migrate Down
+DROP TABLE `sys_account_api_key`;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE `sys_account_login_log` (
+    `id` bigint(20) NOT NULL AUTO_INCREMENT,
+    `sys_account_id` bigint(20) NOT NULL DEFAULT '0' COMMENT 'ref:sys_account.id, 0:帳號不存在',
+    `account` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `ip` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `user_agent` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `is_success` tinyint(4) NOT NULL DEFAULT '0' COMMENT '0:失敗 1:成功',
+    `reason` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_sys_account_id_created_at` (`sys_account_id`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +migrate Down
+DROP TABLE `sys_account_login_log`;
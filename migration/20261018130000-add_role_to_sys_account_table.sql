@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE `sys_account`
+    ADD COLUMN `role` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'viewer' COMMENT 'admin, editor, viewer' AFTER `name`;
+-- 既有帳號維持原本可操作所有功能的權限
+UPDATE `sys_account` SET `role` = 'admin';
+-- +migrate Down
+ALTER TABLE `sys_account`
+    DROP COLUMN `role`;
@@ -0,0 +1,8 @@
+-- +migrate Up
+-- 將明文 secret 轉換為 sha256 hash，格式與 pkg/client_secret 相同，data 內的 secret 一併更新
+UPDATE `oauth_client`
+SET `secret` = CONCAT('sha256$', SHA2(`secret`, 256)),
+    `data`   = IF(JSON_VALID(`data`), JSON_SET(`data`, '$.secret', `secret`), `data`)
+WHERE `secret` != '' AND `secret` NOT LIKE 'sha256$%';
+-- +migrate Down
+-- hash 無法還原為明文，須重新產生 client secret
@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE `oauth_client`
+    ADD COLUMN `secret_created_at` datetime DEFAULT NULL AFTER `secret`,
+    ADD COLUMN `previous_secret` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '輪替前的 secret，於 previous_secret_expired_at 前仍可使用' AFTER `secret_created_at`,
+    ADD COLUMN `previous_secret_created_at` datetime DEFAULT NULL AFTER `previous_secret`,
+    ADD COLUMN `previous_secret_expired_at` datetime DEFAULT NULL AFTER `previous_secret_created_at`;
+UPDATE `oauth_client` SET `secret_created_at` = `created_at`;
+-- +migrate Down
+ALTER TABLE `oauth_client`
+    DROP COLUMN `secret_created_at`,
+    DROP COLUMN `previous_secret`,
+    DROP COLUMN `previous_secret_created_at`,
+    DROP COLUMN `previous_secret_expired_at`;
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE `oauth_client_redirect_uri` (
+    `id` bigint(20) NOT NULL AUTO_INCREMENT,
+    `oauth_client_id` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'ref:oauth_client.id',
+    `redirect_uri` varchar(512) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_oauth_client_id` (`oauth_client_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- 既有 client 的 domain 須通過 redirect uri 驗證才可寫入，由 make migrate-data 執行
+-- +migrate Down
+UPDATE `oauth_client` SET `data` = JSON_REMOVE(`data`, '$.redirect_uris') WHERE JSON_VALID(`data`);
+DROP TABLE `oauth_client_redirect_uri`;
@@ -0,0 +1,24 @@
+-- +migrate Up
+ALTER TABLE `oauth_client`
+    ADD COLUMN `grant_types` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '以空白分隔' AFTER `icon_path`,
+    ADD COLUMN `response_types` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '以空白分隔' AFTER `grant_types`,
+    ADD COLUMN `pkce_required` tinyint(4) NOT NULL DEFAULT '0' COMMENT '0:選用 1:必須使用 PKCE' AFTER `response_types`,
+    ADD COLUMN `pkce_s256_only` tinyint(4) NOT NULL DEFAULT '0' COMMENT '0:允許 plain 1:只允許 S256' AFTER `pkce_required`;
+-- 既有 client 維持 authorization code 流程
+UPDATE `oauth_client`
+SET `grant_types`    = 'authorization_code refresh_token',
+    `response_types` = 'code',
+    `data`           = IF(JSON_VALID(`data`), JSON_SET(`data`,
+                           '$.grant_types', 'authorization_code refresh_token',
+                           '$.response_types', 'code',
+                           '$.pkce_required', CAST('false' AS JSON),
+                           '$.pkce_s256_only', CAST('false' AS JSON)), `data`);
+-- +migrate Down
+UPDATE `oauth_client`
+SET `data` = JSON_REMOVE(`data`, '$.grant_types', '$.response_types', '$.pkce_required', '$.pkce_s256_only')
+WHERE JSON_VALID(`data`);
+ALTER TABLE `oauth_client`
+    DROP COLUMN `grant_types`,
+    DROP COLUMN `response_types`,
+    DROP COLUMN `pkce_required`,
+    DROP COLUMN `pkce_s256_only`;
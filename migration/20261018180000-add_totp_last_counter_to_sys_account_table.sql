@@ -0,0 +1,6 @@
+-- +migrate Up
+ALTER TABLE `sys_account`
+    ADD COLUMN `totp_last_counter` bigint(20) NOT NULL DEFAULT '0' COMMENT '最後一次通過驗證的 TOTP 時間區間，同一區間的 code 不可重複使用' AFTER `totp_secret`;
+-- +migrate Down
+ALTER TABLE `sys_account`
+    DROP COLUMN `totp_last_counter`;
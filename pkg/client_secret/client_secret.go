@@ -0,0 +1,55 @@
+package client_secret
+
+import (
+	"crypto/subtle"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/helper"
+	"strings"
+	"time"
+)
+
+// 由伺服器產生的 secret 為 256 bits 的隨機值，以 sha256 保存即可，不需要 argon2 等慢速 hash
+const hashPrefix = "sha256$"
+
+// Generate 產生新的 client secret，明文只在建立或輪替時回傳一次
+func Generate() (string, error) {
+	return helper.RandomUrlSafe(32)
+}
+
+// Hash 回傳保存於 oauth_client.secret 的 hash
+func Hash(secret string) string {
+	return hashPrefix + helper.Sha256Str(secret)
+}
+
+// IsHashed 判斷保存的 secret 是否已是 hash
+func IsHashed(stored string) bool {
+	return strings.HasPrefix(stored, hashPrefix)
+}
+
+// Verify 驗證 client 帶入的 secret，供授權伺服器驗證 client credentials 使用
+// 尚未轉換的舊資料以明文比對
+func Verify(stored, secret string) bool {
+	if stored == "" || secret == "" {
+		return false
+	}
+
+	expected := secret
+	if IsHashed(stored) {
+		expected = Hash(secret)
+	}
+
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(expected)) == 1
+}
+
+// VerifyClient 驗證 client 的 secret，輪替前的 secret 於寬限期內仍可使用
+func VerifyClient(c *model.OauthClient, secret string, now time.Time) bool {
+	if Verify(c.Secret, secret) {
+		return true
+	}
+
+	if c.PreviousSecret == "" || !now.Before(c.PreviousSecretExpiredAt) {
+		return false
+	}
+
+	return Verify(c.PreviousSecret, secret)
+}
@@ -0,0 +1,81 @@
+package client_secret
+
+import (
+	"oauth2-console-go/dto/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	// Act
+	secret, err := Generate()
+	other, _ := Generate()
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, secret, 43)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestVerify(t *testing.T) {
+	// Arrange
+	secret, _ := Generate()
+	hash := Hash(secret)
+
+	testCases := []struct {
+		Name   string
+		Stored string
+		Secret string
+		Want   bool
+	}{
+		{"hashed", hash, secret, true},
+		{"hashed wrong secret", hash, secret + "x", false},
+		{"hash as secret", hash, hash, false},
+		{"legacy plain text", "billing-secret", "billing-secret", true},
+		{"legacy wrong secret", "billing-secret", "billing", false},
+		{"empty stored", "", "", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			// Act
+			ok := Verify(tc.Stored, tc.Secret)
+
+			// Assert
+			assert.Equal(t, tc.Want, ok)
+		})
+	}
+
+	assert.True(t, IsHashed(hash))
+	assert.False(t, IsHashed(secret))
+}
+
+// 與 migration 中 MySQL 的 SHA2(secret, 256) 結果一致
+func TestHash(t *testing.T) {
+	assert.Equal(t, "sha256$2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b", Hash("secret"))
+}
+
+func TestVerifyClient(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	secret, _ := Generate()
+	previous, _ := Generate()
+	c := model.OauthClient{
+		Secret:                  Hash(secret),
+		PreviousSecret:          Hash(previous),
+		PreviousSecretExpiredAt: now.Add(time.Hour),
+	}
+
+	// Assert
+	assert.True(t, VerifyClient(&c, secret, now))
+	assert.True(t, VerifyClient(&c, previous, now))
+	assert.False(t, VerifyClient(&c, previous, now.Add(time.Hour)))
+	assert.False(t, VerifyClient(&c, "wrong", now))
+
+	// 已撤銷
+	c.PreviousSecret = ""
+	assert.False(t, VerifyClient(&c, previous, now))
+}
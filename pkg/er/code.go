@@ -9,6 +9,8 @@ const (
 	AWSInitError               = 400405
 	FirebaseIdTokenError       = 400409
 	DataDuplicateError         = 400410
+	AccountLockedError         = 400411
+	AccountDisabledError       = 400412
 	LimitExceededError         = 400001
 	DecryptError               = 400002
 	UploadFileErrUnknown       = 400900
@@ -9,6 +9,8 @@ var msg = map[int]string{
 	AWSInitError:               "aws sdk init error",
 	FirebaseIdTokenError:       "Firebase IdToken verify error",
 	DataDuplicateError:         "Data duplicate error",
+	AccountLockedError:         "Account locked error",
+	AccountDisabledError:       "Account disabled error",
 	LimitExceededError:         "Limit exceeded error",
 	DecryptError:               "Decrypt error",
 	UnknownError:               "Database unknown error",
@@ -2,6 +2,7 @@ package helper
 
 import (
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"oauth2-console-go/pkg/er"
@@ -28,15 +29,18 @@ func CheckFormUploadImage(c *gin.Context, fieldName string, fileLimit int64) (mu
 		return nil, "", "", fileNilErr
 	}
 	if fileHeader.Size > (fileLimit * MB) {
+		_ = file.Close()
 		reqErr := er.NewAppErr(http.StatusBadRequest, er.UploadFileErrSizeOverLimit, fmt.Sprintf("max file size is %dMB.", fileLimit), nil)
 		return nil, "", "", reqErr
 	}
 	if len(fileHeader.Filename) > 50 {
+		_ = file.Close()
 		reqErr := er.NewAppErr(http.StatusBadRequest, er.UploadFileErrFileName, "max file name is 50 characters.", nil)
 		return nil, "", "", reqErr
 	}
 	mime, err := mimetype.DetectReader(file)
 	if err != nil || mime == nil {
+		_ = file.Close()
 		fileNilErr := er.NewAppErr(http.StatusBadRequest, er.UploadFileErrEmpty, "the file is empty or isn't exist.", nil)
 		return nil, "", "", fileNilErr
 	}
@@ -46,10 +50,17 @@ func CheckFormUploadImage(c *gin.Context, fieldName string, fileLimit int64) (mu
 	case ImgTypePng:
 	default:
 		typeErr := er.NewAppErr(http.StatusBadRequest, er.UploadFileErrTypeNotMatch, "file type not allowed.", nil)
-		_ = c.Error(typeErr)
+		_ = file.Close()
 		return nil, "", "", typeErr
 	}
-	defer file.Close()
+
+	// 偵測檔案類型時已讀取部分內容，回到檔案開頭，檔案由呼叫端關閉
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		_ = file.Close()
+		readErr := er.NewAppErr(http.StatusBadRequest, er.UploadFileErrUnknown, err.Error(), err)
+		return nil, "", "", readErr
+	}
 
 	return file, fileHeader.Filename, fileExtension, nil
 }
@@ -3,6 +3,7 @@ package helper
 import (
 	"crypto/md5"
 	"crypto/sha1"
+	"crypto/sha256"
 	"fmt"
 	"golang.org/x/crypto/scrypt"
 	"io"
@@ -29,3 +30,10 @@ func Md5Str(str string) (string, error) {
 	hash := fmt.Sprintf("%x", m.Sum(nil))
 	return hash, err
 }
+
+func Sha256Str(str string) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(str))
+	bs := h.Sum(nil)
+	return fmt.Sprintf("%x", bs)
+}
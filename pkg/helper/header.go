@@ -0,0 +1,34 @@
+package helper
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderAuthorization = "Authorization"
+	HeaderLegacyBearer  = "Bearer"
+	HeaderApiKey        = "X-Api-Key"
+	bearerPrefix        = "Bearer "
+)
+
+// GetBearerToken 取得 request 帶入的 JWT，優先使用 Authorization: Bearer <jwt>
+// 若是使用舊版的 Bearer header，isLegacy 回傳 true
+func GetBearerToken(r *http.Request) (token string, isLegacy bool) {
+	auth := r.Header.Get(HeaderAuthorization)
+	if len(auth) > len(bearerPrefix) && strings.EqualFold(auth[:len(bearerPrefix)], bearerPrefix) {
+		return strings.TrimSpace(auth[len(bearerPrefix):]), false
+	}
+
+	token = r.Header.Get(HeaderLegacyBearer)
+	if token != "" {
+		return token, true
+	}
+
+	return "", false
+}
+
+// GetApiKey 取得 request 帶入的 console api key
+func GetApiKey(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(HeaderApiKey))
+}
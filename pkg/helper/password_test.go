@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+)
+
+func TestHashPassword(t *testing.T) {
+	// Act
+	hash1, err1 := HashPassword("A12345678")
+	hash2, err2 := HashPassword("A12345678")
+
+	// Assert
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.True(t, strings.HasPrefix(hash1, "$argon2id$v=19$m=65536,t=3,p=2$"))
+	// 相同密碼使用不同 salt
+	assert.NotEqual(t, hash1, hash2)
+}
+
+func TestVerifyPassword(t *testing.T) {
+	// Arrange
+	hash, _ := HashPassword("A12345678")
+
+	// Act & Assert
+	ok, needsRehash := VerifyPassword(hash, "A12345678")
+	assert.True(t, ok)
+	assert.False(t, needsRehash)
+
+	ok, _ = VerifyPassword(hash, "123456")
+	assert.False(t, ok)
+
+	// 竄改參數
+	tampered := strings.Replace(hash, "t=3", "t=1", 1)
+	ok, _ = VerifyPassword(tampered, "A12345678")
+	assert.False(t, ok)
+
+	// 舊參數產生的 hash 需重新產生
+	salt := []byte("0123456789abcdef")
+	key := argon2.IDKey([]byte("A12345678"), salt, 1, 32*1024, 1, 32)
+	old := fmt.Sprintf("$argon2id$v=19$m=32768,t=1,p=1$%s$%s",
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+	ok, needsRehash = VerifyPassword(old, "A12345678")
+	assert.True(t, ok)
+	assert.True(t, needsRehash)
+
+	// 格式錯誤
+	ok, _ = VerifyPassword("$argon2id$v=19$bad", "A12345678")
+	assert.False(t, ok)
+}
+
+func TestVerifyPassword_Legacy(t *testing.T) {
+	// Arrange
+	_ = os.Setenv("SCRYPT_SALT", "test_salt")
+	legacy := ScryptStr("A12345678")
+
+	// Act & Assert
+	ok, needsRehash := VerifyPassword(legacy, "A12345678")
+	assert.True(t, ok)
+	assert.True(t, needsRehash)
+
+	ok, needsRehash = VerifyPassword(legacy, "123456")
+	assert.False(t, ok)
+	assert.False(t, needsRehash)
+}
@@ -0,0 +1,48 @@
+package helper
+
+import (
+	"net/http"
+	"oauth2-console-go/pkg/er"
+	"strings"
+)
+
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// ParseSort 解析 sort=field,-field，欄位前加 - 表示遞減
+// allowed 為 api 欄位名稱對應的資料庫欄位，不在清單內的欄位一律拒絕
+func ParseSort(sort string, allowed map[string]string) ([]SortField, error) {
+	fields := make([]SortField, 0)
+	if strings.TrimSpace(sort) == "" {
+		return fields, nil
+	}
+
+	used := map[string]bool{}
+	for _, name := range strings.Split(sort, ",") {
+		name = strings.TrimSpace(name)
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+
+		column, ok := allowed[name]
+		if !ok {
+			sortErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "sort field '"+name+"' is not allowed.", nil)
+			return nil, sortErr
+		}
+		if used[column] {
+			sortErr := er.NewAppErr(http.StatusBadRequest, er.ErrorParamInvalid, "sort field '"+name+"' is duplicated.", nil)
+			return nil, sortErr
+		}
+		used[column] = true
+
+		fields = append(fields, SortField{Column: column, Desc: desc})
+	}
+
+	return fields, nil
+}
+
+// EscapeLike 跳脫 LIKE 的萬用字元，使用者輸入只做字面比對
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,36 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	// Arrange
+	allowed := map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"created_at": "created_at",
+	}
+
+	// Act
+	fields, err := ParseSort("name, -created_at", allowed)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []SortField{{Column: "name"}, {Column: "created_at", Desc: true}}, fields)
+
+	fields, err = ParseSort("", allowed)
+	assert.Nil(t, err)
+	assert.Len(t, fields, 0)
+
+	for _, sort := range []string{"password", "name,", "name,-name", "id;drop table", "--id"} {
+		_, err = ParseSort(sort, allowed)
+		assert.NotNil(t, err, sort)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%\_a\\b`, EscapeLike(`100%_a\b`))
+}
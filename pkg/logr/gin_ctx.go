@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"io/ioutil"
+	"oauth2-console-go/pkg/helper"
 )
 
 type CtxRequestMeta struct {
@@ -20,7 +21,7 @@ type CtxRequestMeta struct {
 
 func ExtractReqMeta(c *gin.Context) *CtxRequestMeta {
 	meta := CtxRequestMeta{}
-	meta.Token = c.GetHeader("Bearer")
+	meta.Token, _ = helper.GetBearerToken(c.Request)
 	meta.AppVersion = c.GetHeader("App-Version")
 	meta.AcceptLanguage = c.GetHeader("Accept-Language")
 	// when you read the the body buffer, the buffer will gone
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"strings"
+	"time"
+)
+
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string // text/plain
+}
+
+// Bytes 產生 RFC 5322 格式的郵件內容
+func (m *Message) Bytes() ([]byte, error) {
+	buf := bytes.Buffer{}
+
+	// 防止 header injection
+	for _, v := range append([]string{m.From, m.Subject}, m.To...) {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, fmt.Errorf("mail header must not contain line breaks")
+		}
+	}
+
+	buf.WriteString("From: " + m.From + "\r\n")
+	buf.WriteString("To: " + strings.Join(m.To, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", m.Subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	_, err := w.Write([]byte(m.Body))
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,79 @@
+package mailer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_Bytes(t *testing.T) {
+	// Arrange
+	msg := Message{
+		From:    "noreply@example.com",
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "重設密碼",
+		Body:    "hello",
+	}
+
+	// Act
+	data, err := msg.Bytes()
+
+	// Assert
+	assert.Nil(t, err)
+	str := string(data)
+	assert.Contains(t, str, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, str, "Subject: =?utf-8?q?")
+	assert.True(t, strings.HasSuffix(str, "\r\n\r\nhello"))
+
+	// Header injection
+	msg.Subject = "hi\r\nBcc: evil@example.com"
+	_, err = msg.Bytes()
+	assert.NotNil(t, err)
+}
+
+func TestOutbox_Send(t *testing.T) {
+	// Arrange
+	dir, _ := ioutil.TempDir("", "outbox")
+	defer os.RemoveAll(dir)
+	outbox := NewOutbox(dir, "noreply@example.com")
+
+	// Act
+	err := outbox.Send(&Message{
+		To:      []string{"a@example.com"},
+		Subject: "subject",
+		Body:    "body",
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, outbox.Messages(), 1)
+	assert.Equal(t, "noreply@example.com", outbox.Last().From)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.Len(t, files, 1)
+}
+
+func TestOutbox_MaxMessages(t *testing.T) {
+	// Arrange
+	outbox := NewOutbox("", "noreply@example.com")
+
+	// Act
+	for i := 0; i <= OutboxMaxMessages; i++ {
+		_ = outbox.Send(&Message{
+			To:      []string{"a@example.com"},
+			Subject: fmt.Sprintf("subject %d", i),
+			Body:    "body",
+		})
+	}
+
+	// Assert
+	list := outbox.Messages()
+	assert.Len(t, list, OutboxMaxMessages)
+	assert.Equal(t, "subject 1", list[0].Subject)
+	assert.Equal(t, fmt.Sprintf("subject %d", OutboxMaxMessages), outbox.Last().Subject)
+}
@@ -0,0 +1,86 @@
+package mailer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// OutboxMaxMessages 記憶體中保留的郵件數量上限，超過時移除最舊的郵件
+const OutboxMaxMessages = 100
+
+// Outbox 不實際寄出郵件，保留在記憶體並寫入 dir(.eml)，用於本機開發及測試
+type Outbox struct {
+	dir  string
+	from string
+
+	mu       sync.Mutex
+	sent     int
+	messages []*Message
+}
+
+// NewOutbox dir 為空時只保留在記憶體
+func NewOutbox(dir, from string) *Outbox {
+	return &Outbox{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (o *Outbox) Send(msg *Message) error {
+	if msg.From == "" {
+		msg.From = o.from
+	}
+
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.dir != "" {
+		err = os.MkdirAll(o.dir, 0755)
+		if err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("%s-%03d.eml", time.Now().UTC().Format("20060102T150405.000000000"), o.sent)
+		err = ioutil.WriteFile(filepath.Join(o.dir, name), data, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.sent++
+	o.messages = append(o.messages, msg)
+	if len(o.messages) > OutboxMaxMessages {
+		o.messages = append(o.messages[:0:0], o.messages[len(o.messages)-OutboxMaxMessages:]...)
+	}
+	return nil
+}
+
+// Messages 已寄出的郵件
+func (o *Outbox) Messages() []*Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	list := make([]*Message, len(o.messages))
+	copy(list, o.messages)
+	return list
+}
+
+// Last 最後一封寄出的郵件
+func (o *Outbox) Last() *Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.messages) == 0 {
+		return nil
+	}
+	return o.messages[len(o.messages)-1]
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+type SmtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSmtpMailer username 為空時不進行 SMTP AUTH
+func NewSmtpMailer(host string, port int, username, password, from string) Mailer {
+	m := SmtpMailer{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+	}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &m
+}
+
+func (m *SmtpMailer) Send(msg *Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, m.from, msg.To, data)
+}
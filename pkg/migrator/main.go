@@ -0,0 +1,34 @@
+package main
+
+import (
+	"oauth2-console-go/config"
+	"oauth2-console-go/driver"
+	"oauth2-console-go/pkg/logr"
+	"oauth2-console-go/pkg/migrator/step"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// 執行 sql-migrate 之後的資料遷移，任一步驟失敗時以非 0 結束
+func main() {
+	config.InitEnv()
+
+	logger := logr.NewLogger()
+
+	orm, err := driver.NewXorm()
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	for _, s := range step.AllStep() {
+		logger.Info(s.Name)
+		err = s.Run(orm, logger)
+		if err != nil {
+			logger.Error(s.Name + " Failed")
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+}
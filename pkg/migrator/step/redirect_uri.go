@@ -0,0 +1,65 @@
+package step
+
+import (
+	"oauth2-console-go/dto/model"
+	clientRepo "oauth2-console-go/internal/oauth/client/repository"
+	"oauth2-console-go/internal/oauth/library"
+
+	"go.uber.org/zap"
+	"xorm.io/xorm"
+)
+
+func AllStep() []Step {
+	return []Step{
+		{
+			Name: "Backfill Oauth Client Redirect Uri",
+			Run:  BackfillRedirectUri,
+		},
+	}
+}
+
+// BackfillRedirectUri 以既有 client 的 domain 建立第一個 redirect uri
+// 已有 redirect uri 的 client 略過，domain 未通過驗證時不寫入並列出，須由 client 擁有者重新設定
+func BackfillRedirectUri(orm *xorm.EngineGroup, logger *zap.Logger) error {
+	clients := make([]*model.OauthClient, 0)
+	err := orm.Asc("id").Find(&clients)
+	if err != nil {
+		return err
+	}
+
+	ocr := clientRepo.NewRepository(orm)
+	migrated, skipped := 0, 0
+	for _, clt := range clients {
+		list, err := ocr.FindRedirectUri(clt.Id)
+		if err != nil {
+			return err
+		}
+		if len(list) > 0 {
+			continue
+		}
+
+		err = library.ValidateRedirectUri(clt.Domain)
+		if err != nil {
+			skipped++
+			logger.Warn("skip invalid redirect uri.",
+				zap.String("client_id", clt.Id),
+				zap.Int("sys_account_id", clt.SysAccountId),
+				zap.String("domain", clt.Domain),
+				zap.String("reason", err.Error()))
+			continue
+		}
+
+		err = ocr.InsertRedirectUri(&model.OauthClientRedirectUri{
+			OauthClientId: clt.Id,
+			RedirectUri:   clt.Domain,
+		})
+		if err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	logger.Info("backfill redirect uri done.", zap.Int("migrated", migrated), zap.Int("skipped", skipped))
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package step
+
+import (
+	"go.uber.org/zap"
+	"xorm.io/xorm"
+)
+
+// Step 無法以 SQL 完成的資料遷移，於 sql-migrate 之後執行，須可重複執行
+type Step struct {
+	Name string
+	Run  func(orm *xorm.EngineGroup, logger *zap.Logger) error
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"oauth2-console-go/pkg/helper"
 	"oauth2-console-go/pkg/logr"
 	"time"
 )
@@ -120,7 +121,11 @@ func Cache(store CacheStore) gin.HandlerFunc {
 func CachePage(store CacheStore, expire time.Duration, handle gin.HandlerFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var cache responseCache
-		key := CreateKey(fmt.Sprintf("%s%s%s%s", c.Request.URL, c.Request.Method, c.Request.Header.Get("Bearer"), c.Request.Body))
+		token, _ := helper.GetBearerToken(c.Request)
+		if token == "" {
+			token = helper.GetApiKey(c.Request)
+		}
+		key := CreateKey(fmt.Sprintf("%s%s%s%s", c.Request.URL, c.Request.Method, token, c.Request.Body))
 		if err := store.Get(key, &cache); err != nil {
 			if err != ErrCacheMiss {
 				logr.L.Error(err.Error())
@@ -3,19 +3,22 @@ package seed
 import (
 	"encoding/json"
 	"oauth2-console-go/dto/model"
+	"oauth2-console-go/pkg/client_secret"
 
 	"xorm.io/xorm"
 )
 
 func CreateOauthClient(engine *xorm.Engine, id, name, secret, domain, scope string) error {
 	con := model.OauthClient{
-		Id:           id,
-		SysAccountId: 0,
-		Name:         name,
-		Secret:       secret,
-		Domain:       domain,
-		Scope:        scope,
-		IconPath:     "",
+		Id:            id,
+		SysAccountId:  0,
+		Name:          name,
+		Secret:        client_secret.Hash(secret),
+		Domain:        domain,
+		Scope:         scope,
+		IconPath:      "",
+		GrantTypes:    model.DefaultGrantTypes,
+		ResponseTypes: model.DefaultResponseTypes,
 	}
 
 	jsonData, _ := json.Marshal(con)
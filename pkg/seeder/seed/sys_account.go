@@ -15,6 +15,7 @@ func CreateSysAccount(engine *xorm.Engine, account, name, email, phone string) e
 		Email:    email,
 		Password: defaultPassword,
 		Name:     name,
+		Role:     model.RoleAdmin,
 	}
 
 	_, err := engine.Insert(&con)
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local 儲存於本機目錄，由 api server 以 baseUrl 提供靜態檔案
+type Local struct {
+	dir     string
+	baseUrl string
+}
+
+func NewLocal(dir, baseUrl string) *Local {
+	return &Local{
+		dir:     dir,
+		baseUrl: strings.TrimRight(baseUrl, "/"),
+	}
+}
+
+func (l *Local) Put(key string, r io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(l.dir, filepath.FromSlash(key))
+	err = os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	// 先寫入暫存檔再更名，避免讀到寫入一半的檔案
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (l *Local) Delete(key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(l.dir, filepath.FromSlash(key)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *Local) Url(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return l.baseUrl + "/" + key, nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocal(t *testing.T) {
+	// Arrange
+	dir, _ := ioutil.TempDir("", "storage")
+	defer os.RemoveAll(dir)
+	st := NewLocal(dir, "http://localhost:8080/storage/")
+	data := []byte("icon")
+	key := ContentKey("oauth_client/icon", data, ".png")
+
+	// Act
+	err := st.Put(key, bytes.NewReader(data), int64(len(data)), "image/png")
+
+	// Assert
+	assert.Nil(t, err)
+	saved, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(key)))
+	assert.Nil(t, err)
+	assert.Equal(t, data, saved)
+
+	u, err := st.Url(key)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:8080/storage/"+key, u)
+
+	err = st.Delete(key)
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, filepath.FromSlash(key)))
+	assert.True(t, os.IsNotExist(err))
+
+	// 刪除不存在的檔案
+	err = st.Delete(key)
+	assert.Nil(t, err)
+}
+
+func TestLocal_KeyOutsideDir(t *testing.T) {
+	// Arrange
+	dir, _ := ioutil.TempDir("", "storage")
+	defer os.RemoveAll(dir)
+	st := NewLocal(filepath.Join(dir, "public"), "/storage")
+
+	// Act
+	err := st.Put("../../secret.txt", bytes.NewReader([]byte("x")), 1, "")
+
+	// Assert
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "public", "secret.txt"))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "secret.txt"))
+	assert.True(t, os.IsNotExist(err))
+
+	err = st.Put("", bytes.NewReader(nil), 0, "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3Service         = "s3"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+type S3Config struct {
+	Endpoint  string // e.g. https://s3.ap-northeast-1.amazonaws.com 或 MinIO 位址
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool          // MinIO 等相容服務使用 endpoint/bucket/key
+	UrlExpire time.Duration // 簽章網址有效期限
+}
+
+// S3 相容 S3 API 的物件儲存，使用 AWS Signature Version 4 簽章
+type S3 struct {
+	conf   S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3(conf S3Config) *S3 {
+	conf.Endpoint = strings.TrimRight(conf.Endpoint, "/")
+	if conf.Region == "" {
+		conf.Region = "us-east-1"
+	}
+	if conf.UrlExpire <= 0 {
+		conf.UrlExpire = time.Hour
+	}
+
+	return &S3{
+		conf:   conf,
+		client: &http.Client{Timeout: time.Second * 30},
+		now:    time.Now,
+	}
+}
+
+func (s *S3) Put(key string, r io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return s.do(req)
+}
+
+func (s *S3) Delete(key string) error {
+	req, err := s.newRequest(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req)
+}
+
+// Url 產生 presigned GET 網址
+func (s *S3) Url(key string) (string, error) {
+	u, err := s.objectUrl(key)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now().UTC()
+	scope := s.credentialScope(now)
+
+	q := url.Values{}
+	q.Set("X-Amz-Algorithm", s3Algorithm)
+	q.Set("X-Amz-Credential", s.conf.AccessKey+"/"+scope)
+	q.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(s.conf.UrlExpire/time.Second)))
+	q.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery(q),
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+
+	q.Set("X-Amz-Signature", s.sign(now, scope, canonicalRequest))
+	u.RawQuery = canonicalQuery(q)
+
+	return u.String(), nil
+}
+
+func (s *S3) newRequest(method, key string, body io.Reader) (*http.Request, error) {
+	u, err := s.objectUrl(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (s *S3) do(req *http.Request) error {
+	s.signRequest(req)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 %s %s error: %d %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// signRequest 以 header 簽章，payload 不計算 hash(UNSIGNED-PAYLOAD)
+func (s *S3) signRequest(req *http.Request) {
+	now := s.now().UTC()
+	scope := s.credentialScope(now)
+
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": s3UnsignedPayload,
+		"x-amz-date":           now.Format(s3TimeFormat),
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		headers["content-type"] = ct
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + strings.TrimSpace(headers[name]) + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	signature := s.sign(now, scope, canonicalRequest)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", s3Algorithm, s.conf.AccessKey, scope, signedHeaders, signature))
+}
+
+func (s *S3) sign(now time.Time, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+s.conf.SecretKey), now.Format(s3DateFormat))
+	key = hmacSha256(key, s.conf.Region)
+	key = hmacSha256(key, s3Service)
+	key = hmacSha256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSha256(key, stringToSign))
+}
+
+func (s *S3) credentialScope(now time.Time) string {
+	return strings.Join([]string{now.Format(s3DateFormat), s.conf.Region, s3Service, "aws4_request"}, "/")
+}
+
+func (s *S3) objectUrl(key string) (*url.URL, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(s.conf.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %q is not valid", s.conf.Endpoint)
+	}
+
+	if s.conf.PathStyle {
+		u.Path = "/" + s.conf.Bucket + "/" + key
+	} else {
+		u.Host = s.conf.Bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+
+	return u, nil
+}
+
+// canonicalQuery 依 SigV4 規則排序並編碼 query，空白編碼為 %20
+func canonicalQuery(q url.Values) string {
+	return strings.ReplaceAll(q.Encode(), "+", "%20")
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
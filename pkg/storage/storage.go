@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+type Storage interface {
+	// Put 寫入檔案，相同 key 直接覆寫
+	Put(key string, r io.Reader, size int64, contentType string) error
+	// Delete 刪除檔案，檔案不存在時不回傳錯誤
+	Delete(key string) error
+	// Url 取得檔案的存取網址，私有 bucket 回傳有時效的簽章網址
+	Url(key string) (string, error)
+}
+
+// ContentKey 以檔案內容的 sha256 產生 key，相同的檔案只保存一份
+func ContentKey(prefix string, data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	return path.Join(prefix, fmt.Sprintf("%x%s", sum, ext))
+}
+
+// cleanKey 避免 key 跳出儲存目錄
+func cleanKey(key string) (string, error) {
+	key = strings.TrimLeft(path.Clean("/"+key), "/")
+	if key == "" || key == "." {
+		return "", fmt.Errorf("storage key is required")
+	}
+	return key, nil
+}
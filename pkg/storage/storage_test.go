@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentKey(t *testing.T) {
+	// Act
+	key1 := ContentKey("oauth_client/icon", []byte("a"), ".png")
+	key2 := ContentKey("oauth_client/icon", []byte("a"), ".png")
+	key3 := ContentKey("oauth_client/icon", []byte("b"), ".png")
+
+	// Assert
+	assert.Equal(t, "oauth_client/icon/ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb.png", key1)
+	assert.Equal(t, key1, key2)
+	assert.NotEqual(t, key1, key3)
+}
@@ -0,0 +1,85 @@
+package totp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 Appendix B 的 SHA1 測試向量，取後 6 碼
+func TestCode(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890"
+
+	testCases := []struct {
+		Unix int64
+		Code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("Unix:%d", tc.Unix), func(t *testing.T) {
+			code, err := Code(secret, time.Unix(tc.Unix, 0))
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Code, code)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// Arrange
+	secret, err := GenerateSecret()
+	assert.Nil(t, err)
+
+	now := time.Now()
+	code, _ := Code(secret, now)
+	prevCode, _ := Code(secret, now.Add(-Period*time.Second))
+	oldCode, _ := Code(secret, now.Add(-3*Period*time.Second))
+
+	// Act & Assert
+	assert.True(t, Validate(secret, code, now))
+	assert.True(t, Validate(secret, prevCode, now))
+	if oldCode != code && oldCode != prevCode {
+		assert.False(t, Validate(secret, oldCode, now))
+	}
+	assert.False(t, Validate(secret, "12345", now))
+	assert.False(t, Validate("not-base32!", code, now))
+}
+
+func TestValidateCounter(t *testing.T) {
+	// Arrange
+	secret, _ := GenerateSecret()
+	now := time.Unix(1700000000, 0)
+	code, _ := Code(secret, now)
+	prevCode, _ := Code(secret, now.Add(-Period*time.Second))
+
+	// Act
+	counter, ok := ValidateCounter(secret, code, now)
+	prevCounter, prevOk := ValidateCounter(secret, prevCode, now)
+
+	// Assert
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/Period, counter)
+	if prevCode != code {
+		assert.True(t, prevOk)
+		assert.Equal(t, counter-1, prevCounter)
+	}
+}
+
+func TestKeyUri(t *testing.T) {
+	// Act
+	uri := KeyUri("OAuth Console", "sys_account", "JBSWY3DPEHPK3PXP")
+
+	// Assert
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/OAuth%20Console:sys_account?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=OAuth+Console")
+}
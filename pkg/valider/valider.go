@@ -6,6 +6,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"reflect"
 	"strings"
+	"unicode"
 )
 
 // use a single instance of Validate, it caches struct info
@@ -39,4 +40,33 @@ func Init() {
 
 		return t
 	})
+
+	_ = Validate.RegisterValidation("password", ValidatePassword)
+	_ = Validate.RegisterTranslation("password", trans, func(ut ut.Translator) error {
+		return ut.Add("password", "{0} must be 8-64 characters and contain both letters and numbers!", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("password", fe.Field())
+
+		return t
+	})
+}
+
+// ValidatePassword 密碼規則：長度 8-64，至少包含一個英文字母及一個數字
+func ValidatePassword(fl validator.FieldLevel) bool {
+	pw := fl.Field().String()
+	if len(pw) < 8 || len(pw) > 64 {
+		return false
+	}
+
+	hasLetter, hasDigit := false, false
+	for _, r := range pw {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	return hasLetter && hasDigit
 }
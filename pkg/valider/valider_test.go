@@ -0,0 +1,28 @@
+package valider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePassword(t *testing.T) {
+	Init()
+
+	testCases := []struct {
+		Password string
+		WantOk   bool
+	}{
+		{"A12345678", true},
+		{"abcdefg1", true},
+		{"12345678", false},
+		{"abcdefgh", false},
+		{"a1", false},
+		{"a1234567890123456789012345678901234567890123456789012345678901234", false},
+	}
+
+	for _, tc := range testCases {
+		err := Validate.Var(tc.Password, "password")
+		assert.Equal(t, tc.WantOk, err == nil, tc.Password)
+	}
+}
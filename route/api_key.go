@@ -0,0 +1,30 @@
+package route
+
+import (
+	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/middleware"
+	"oauth2-console-go/pkg/request_cache"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ApiKeyV1(r *gin.Engine, store request_cache.CacheStore) {
+	v1Auth := r.Group("/v1/api-keys")
+	v1Auth.Use(middleware.TokenAuth())
+
+	// Api key 列表
+	v1Auth.GET("/", func(c *gin.Context) {
+		apiV1.ListApiKey(c)
+	})
+
+	// 新增 Api key
+	v1Auth.POST("/", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.AddApiKey(c)
+	}))
+
+	// 撤銷 Api key
+	v1Auth.DELETE("/:id", func(c *gin.Context) {
+		apiV1.DeleteApiKey(c)
+	})
+}
@@ -0,0 +1,29 @@
+package route
+
+import (
+	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/middleware"
+	"oauth2-console-go/pkg/request_cache"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InvitationV1(r *gin.Engine, store request_cache.CacheStore) {
+	v1 := r.Group("/v1/invitations")
+
+	// 接受邀請並建立帳號
+	v1.POST("/accept", func(c *gin.Context) {
+		apiV1.AcceptInvitation(c)
+	})
+
+	v1Auth := r.Group("/v1/invitations")
+	v1Auth.Use(middleware.TokenAuth())
+	v1Auth.Use(middleware.RequireRole(model.RoleAdmin))
+
+	// 邀請新帳號
+	v1Auth.POST("", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.AddInvitation(c)
+	}))
+}
@@ -0,0 +1,30 @@
+package route
+
+import (
+	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/middleware"
+	"oauth2-console-go/pkg/request_cache"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MeV1(r *gin.Engine, store request_cache.CacheStore) {
+	v1Auth := r.Group("/v1/me")
+	v1Auth.Use(middleware.TokenAuth())
+
+	// 取得個人資料
+	v1Auth.GET("", func(c *gin.Context) {
+		apiV1.GetMe(c)
+	})
+
+	// 編輯個人資料
+	v1Auth.PUT("", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.EditMe(c)
+	}))
+
+	// 變更密碼
+	v1Auth.POST("/password", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.ChangeMyPassword(c)
+	}))
+}
@@ -0,0 +1,30 @@
+package route
+
+import (
+	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/middleware"
+	"oauth2-console-go/pkg/request_cache"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MfaV1(r *gin.Engine, store request_cache.CacheStore) {
+	v1Auth := r.Group("/v1/mfa")
+	v1Auth.Use(middleware.TokenAuth())
+
+	// 設定 TOTP 兩步驟驗證
+	v1Auth.POST("/totp", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.EnrollMfaTotp(c)
+	}))
+
+	// 啟用 TOTP 兩步驟驗證
+	v1Auth.POST("/totp/activate", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.ActivateMfaTotp(c)
+	}))
+
+	// 停用 TOTP 兩步驟驗證
+	v1Auth.DELETE("/totp", func(c *gin.Context) {
+		apiV1.DisableMfaTotp(c)
+	})
+}
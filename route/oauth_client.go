@@ -2,6 +2,7 @@ package route
 
 import (
 	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/dto/model"
 	"oauth2-console-go/middleware"
 	"oauth2-console-go/pkg/request_cache"
 	"time"
@@ -24,12 +25,52 @@ func OauthClientV1(r *gin.Engine, store request_cache.CacheStore) {
 	})
 
 	// 新增 Oauth Client
-	v1Auth.POST("/", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+	v1Auth.POST("/", middleware.RequireRole(model.RoleEditor), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
 		apiV1.AddOauthClient(c)
 	}))
 
 	// 編輯 Oauth Client
-	v1Auth.PUT("/:id", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+	v1Auth.PUT("/:id", middleware.RequireRole(model.RoleEditor), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
 		apiV1.EditOauthClient(c)
 	}))
+
+	// 轉移 Oauth Client 擁有者
+	v1Auth.POST("/:id/transfer", middleware.RequireRole(model.RoleEditor), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.TransferOauthClient(c)
+	}))
+
+	// Oauth Client secret 列表
+	v1Auth.GET("/:id/secrets", func(c *gin.Context) {
+		apiV1.ListOauthClientSecret(c)
+	})
+
+	// 輪替 Oauth Client secret
+	v1Auth.POST("/:id/secrets", middleware.RequireRole(model.RoleEditor), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.RotateOauthClientSecret(c)
+	}))
+
+	// 撤銷輪替前的 Oauth Client secret
+	v1Auth.DELETE("/:id/secrets/previous", middleware.RequireRole(model.RoleEditor), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.RevokeOauthClientPreviousSecret(c)
+	}))
+
+	// Oauth Client redirect uri 列表
+	v1Auth.GET("/:id/redirect-uris", func(c *gin.Context) {
+		apiV1.ListOauthClientRedirectUri(c)
+	})
+
+	// 登記 Oauth Client redirect uri
+	v1Auth.POST("/:id/redirect-uris", middleware.RequireRole(model.RoleEditor), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.AddOauthClientRedirectUri(c)
+	}))
+
+	// 刪除 Oauth Client redirect uri
+	v1Auth.DELETE("/:id/redirect-uris/:redirect_uri_id", middleware.RequireRole(model.RoleEditor), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.DeleteOauthClientRedirectUri(c)
+	}))
+
+	// 刪除 Oauth Client
+	v1Auth.DELETE("/:id", middleware.RequireRole(model.RoleEditor), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.DeleteOauthClient(c)
+	}))
 }
@@ -2,6 +2,7 @@ package route
 
 import (
 	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/dto/model"
 	"oauth2-console-go/middleware"
 	"oauth2-console-go/pkg/request_cache"
 	"time"
@@ -24,12 +25,12 @@ func OauthScopeV1(r *gin.Engine, store request_cache.CacheStore) {
 	})
 
 	// 新增 Oauth Scope
-	v1Auth.POST("/", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+	v1Auth.POST("/", middleware.RequireRole(model.RoleAdmin), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
 		apiV1.AddOauthScope(c)
 	}))
 
 	// 編輯 Oauth Scope
-	v1Auth.PUT("/:id", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+	v1Auth.PUT("/:id", middleware.RequireRole(model.RoleAdmin), request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
 		apiV1.EditOauthScope(c)
 	}))
 }
@@ -0,0 +1,22 @@
+package route
+
+import (
+	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/pkg/request_cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PasswordV1(r *gin.Engine, store request_cache.CacheStore) {
+	v1 := r.Group("/v1/password")
+
+	// 寄送重設密碼信件
+	v1.POST("/forgot", func(c *gin.Context) {
+		apiV1.ForgotPassword(c)
+	})
+
+	// 重設密碼
+	v1.POST("/reset", func(c *gin.Context) {
+		apiV1.ResetPassword(c)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"oauth2-console-go/config"
 	_ "oauth2-console-go/docs"
+	"oauth2-console-go/driver"
 	"oauth2-console-go/middleware"
 	"oauth2-console-go/pkg/request_cache"
 	"os"
@@ -32,6 +33,11 @@ func Init() *gin.Engine {
 	r.Use(middleware.LogRequest())
 	r.Use(middleware.ErrorResponse())
 
+	// 本機儲存的檔案(client icon)
+	if config.GetStorageDriver() == config.StorageDriverLocal {
+		r.Static(config.StorageLocalRoute, driver.GetStorageLocalDir())
+	}
+
 	// Swagger
 	if mode := gin.Mode(); mode == gin.DebugMode {
 		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -40,11 +46,20 @@ func Init() *gin.Engine {
 	corsConf := cors.DefaultConfig()
 	corsConf.AllowCredentials = true
 	corsConf.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"}
-	corsConf.AllowHeaders = []string{"Origin", "X-Requested-With", "Content-Type", "Accept", "Authorization", "Bearer", "Accept-Language"}
+	corsConf.AllowHeaders = []string{"Origin", "X-Requested-With", "Content-Type", "Accept", "Authorization", "Bearer", "X-Api-Key", "Accept-Language"}
 	corsConf.AllowOriginFunc = config.GetCorsRule
 	r.Use(cors.New(corsConf))
 
+	WellKnown(r)
 	TokenV1(r, store)
+	SessionV1(r, store)
+	MeV1(r, store)
+	PasswordV1(r, store)
+	EmailVerificationV1(r, store)
+	MfaV1(r, store)
+	SysAccountV1(r, store)
+	InvitationV1(r, store)
+	ApiKeyV1(r, store)
 	OauthClientV1(r, store)
 	OauthScopeV1(r, store)
 
@@ -0,0 +1,24 @@
+package route
+
+import (
+	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/middleware"
+	"oauth2-console-go/pkg/request_cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SessionV1(r *gin.Engine, store request_cache.CacheStore) {
+	v1Auth := r.Group("/v1/sessions")
+	v1Auth.Use(middleware.TokenAuth())
+
+	// 登入裝置列表
+	v1Auth.GET("/", func(c *gin.Context) {
+		apiV1.ListSession(c)
+	})
+
+	// 登出指定裝置
+	v1Auth.DELETE("/:id", func(c *gin.Context) {
+		apiV1.RevokeSession(c)
+	})
+}
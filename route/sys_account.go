@@ -0,0 +1,57 @@
+package route
+
+import (
+	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/dto/model"
+	"oauth2-console-go/middleware"
+	"oauth2-console-go/pkg/request_cache"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SysAccountV1(r *gin.Engine, store request_cache.CacheStore) {
+	v1Auth := r.Group("/v1/accounts")
+	v1Auth.Use(middleware.TokenAuth())
+	v1Auth.Use(middleware.RequireRole(model.RoleAdmin))
+
+	// 帳號列表
+	v1Auth.GET("/", func(c *gin.Context) {
+		apiV1.ListSysAccount(c)
+	})
+
+	// 取得帳號
+	v1Auth.GET("/:id", func(c *gin.Context) {
+		apiV1.GetSysAccount(c)
+	})
+
+	// 新增帳號
+	v1Auth.POST("/", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.AddSysAccount(c)
+	}))
+
+	// 編輯帳號
+	v1Auth.PUT("/:id", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.EditSysAccount(c)
+	}))
+
+	// 停用帳號
+	v1Auth.POST("/:id/disable", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.DisableSysAccount(c)
+	}))
+
+	// 重新啟用帳號
+	v1Auth.POST("/:id/enable", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.EnableSysAccount(c)
+	}))
+
+	// 登入紀錄列表
+	v1Auth.GET("/:id/logins", func(c *gin.Context) {
+		apiV1.ListSysAccountLoginLog(c)
+	})
+
+	// 解除帳號鎖定
+	v1Auth.POST("/:id/unlock", request_cache.CachePage(store, time.Second*1, func(c *gin.Context) {
+		apiV1.UnlockSysAccount(c)
+	}))
+}
@@ -2,15 +2,45 @@ package route
 
 import (
 	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/middleware"
 	"oauth2-console-go/pkg/request_cache"
 
 	"github.com/gin-gonic/gin"
 )
 
+func WellKnown(r *gin.Engine) {
+	// 驗證 token 用的公開金鑰
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		apiV1.GetJwks(c)
+	})
+}
+
 func TokenV1(r *gin.Engine, store request_cache.CacheStore) {
 	v1 := r.Group("/v1")
 
 	v1.POST("/token", func(c *gin.Context) {
 		apiV1.GetToken(c)
 	})
+
+	// 兩步驟驗證
+	v1.POST("/token/mfa", func(c *gin.Context) {
+		apiV1.VerifyMfa(c)
+	})
+
+	v1.POST("/token/refresh", func(c *gin.Context) {
+		apiV1.RefreshToken(c)
+	})
+
+	v1Auth := r.Group("/v1/token")
+	v1Auth.Use(middleware.TokenAuth())
+
+	// 登出目前的 token
+	v1Auth.POST("/logout", func(c *gin.Context) {
+		apiV1.Logout(c)
+	})
+
+	// 登出所有裝置
+	v1Auth.POST("/logout-all", func(c *gin.Context) {
+		apiV1.LogoutAll(c)
+	})
 }
@@ -0,0 +1,22 @@
+package route
+
+import (
+	apiV1 "oauth2-console-go/api/v1"
+	"oauth2-console-go/pkg/request_cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+func EmailVerificationV1(r *gin.Engine, store request_cache.CacheStore) {
+	v1 := r.Group("/v1/email-verification")
+
+	// 完成 email 驗證
+	v1.POST("/confirm", func(c *gin.Context) {
+		apiV1.ConfirmEmail(c)
+	})
+
+	// 重新寄送驗證信
+	v1.POST("/resend", func(c *gin.Context) {
+		apiV1.ResendVerification(c)
+	})
+}